@@ -0,0 +1,158 @@
+package erlgo
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+type Atom string
+
+const (
+	True  Atom = "true"
+	False Atom = "false"
+)
+
+func (a Atom) IsInteger() bool {
+	return false
+}
+
+func (a Atom) IsList() bool { return false }
+
+func (a Atom) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+// IsBool reports whether the atom is one of `true` or `false`, which is how
+// Erlang represents booleans.
+func (a Atom) IsBool() bool {
+	return a == True || a == False
+}
+
+func (a Atom) ToBool() (bool, error) {
+	switch a {
+	case True:
+		return true, nil
+	case False:
+		return false, nil
+	default:
+		return false, errors.New("Not a Boolean")
+	}
+}
+
+func (a Atom) Matches(other Term) bool {
+	switch o := other.(type) {
+	case Atom:
+		return a == o
+	default:
+		return false
+	}
+}
+
+func decodeAtom(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != atomExt {
+		return nil, fmt.Errorf("%v is not tagging an atom", tag)
+	}
+
+	length, err := readUint16(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAtomText(b, int(length), false)
+}
+
+func decodeSmallAtom(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != smallAtomExt {
+		return nil, fmt.Errorf("%v is not tagging a small atom", tag)
+	}
+
+	length, err := b.bs.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	return readAtomText(b, int(length), false)
+}
+
+func decodeAtomUtf8(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != atomUtf8Ext {
+		return nil, fmt.Errorf("%v is not tagging an utf8 atom", tag)
+	}
+
+	length, err := readUint16(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAtomText(b, int(length), true)
+}
+
+func decodeSmallAtomUtf8(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != smallAtomUtf8Ext {
+		return nil, fmt.Errorf("%v is not tagging a small utf8 atom", tag)
+	}
+
+	length, err := b.bs.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	return readAtomText(b, int(length), true)
+}
+
+// decodeAtomCacheRef resolves an index into the atom cache references of the
+// distribution header preceding the term.
+func decodeAtomCacheRef(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != atomCacheRef {
+		return nil, fmt.Errorf("%v is not tagging an atom cache reference", tag)
+	}
+
+	index, err := b.bs.ReadByte()
+	if err != nil {
+		return nil, err
+	} else if int(index) >= len(b.refs) {
+		return nil, fmt.Errorf("atom cache reference %v is not in the distribution header", index)
+	}
+
+	return b.refs[index], nil
+}
+
+// readAtomText reads length bytes of atom text. Latin-1 encoded text gets
+// transcoded to UTF-8, so that an Atom always holds valid UTF-8.
+func readAtomText(b ErlExtBinary, length int, isUtf8 bool) (Term, error) {
+	text, err := readBytes(b, length)
+	if err != nil {
+		return nil, err
+	}
+
+	var atom Atom
+	if isUtf8 {
+		if !utf8.Valid(text) {
+			return nil, fmt.Errorf("%q is not a valid utf8 atom", text)
+		}
+		atom = Atom(text)
+	} else {
+		runes := make([]rune, len(text))
+		for i, c := range text {
+			runes[i] = rune(c)
+		}
+		atom = Atom(string(runes))
+	}
+
+	if err := b.state.checkAtom(atom); err != nil {
+		return nil, err
+	}
+
+	return atom, nil
+}
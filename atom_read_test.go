@@ -0,0 +1,74 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var atomTestTable = []struct {
+	Name   string
+	Data   erlgo.ErlExtBinary
+	Expect erlgo.Term
+}{
+	{"ok", erlgo.FromBytes([]byte{131, 100, 0, 2, 111, 107}), erlgo.Atom("ok")},
+	{"empty atom", erlgo.FromBytes([]byte{131, 100, 0, 0}), erlgo.Atom("")},
+	{"ok (small)", erlgo.FromBytes([]byte{131, 115, 2, 111, 107}), erlgo.Atom("ok")},
+	{"true (small utf8)", erlgo.FromBytes([]byte{131, 119, 4, 116, 114, 117, 101}), erlgo.True},
+	{"false (utf8)", erlgo.FromBytes([]byte{131, 118, 0, 5, 102, 97, 108, 115, 101}), erlgo.False},
+	{"latin1 'ö'", erlgo.FromBytes([]byte{131, 100, 0, 1, 246}), erlgo.Atom("ö")},
+	{"latin1 'ö' (small)", erlgo.FromBytes([]byte{131, 115, 1, 246}), erlgo.Atom("ö")},
+	{"utf8 'ö'", erlgo.FromBytes([]byte{131, 119, 2, 195, 182}), erlgo.Atom("ö")},
+	{"utf8 '😀'", erlgo.FromBytes([]byte{131, 118, 0, 4, 240, 159, 152, 128}), erlgo.Atom("😀")},
+}
+
+func TestReadingAtoms(t *testing.T) {
+	for _, test := range atomTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if val, err := test.Data.Decode(); err == nil && !val.Matches(test.Expect) {
+				t.Errorf(`%#v parsed into %#v, expected %#v."`, test.Data, val, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected value %#v.`, test.Data, err, test.Expect)
+			}
+		})
+	}
+}
+
+func TestAtomBooleans(t *testing.T) {
+	for _, test := range []struct {
+		Atom   erlgo.Atom
+		IsBool bool
+		Value  bool
+	}{
+		{erlgo.True, true, true},
+		{erlgo.False, true, false},
+		{erlgo.Atom("ok"), false, false},
+		{erlgo.Atom("True"), false, false},
+	} {
+		t.Run(string(test.Atom), func(t *testing.T) {
+			if test.Atom.IsBool() != test.IsBool {
+				t.Errorf(`%#v.IsBool() returned %v, expected %v.`, test.Atom, !test.IsBool, test.IsBool)
+			}
+			if val, err := test.Atom.ToBool(); test.IsBool && (err != nil || val != test.Value) {
+				t.Errorf(`%#v.ToBool() returned (%v, %v), expected %v.`, test.Atom, val, err, test.Value)
+			} else if !test.IsBool && err == nil {
+				t.Errorf(`%#v.ToBool() returned %v, expected an error.`, test.Atom, val)
+			}
+		})
+	}
+}
+
+func TestReadingInvalidUtf8Atom(t *testing.T) {
+	if val, err := erlgo.FromBytes([]byte{131, 119, 1, 246}).Decode(); err == nil {
+		t.Errorf(`invalid utf8 atom parsed into %#v, expected an error.`, val)
+	}
+}
+
+func BenchmarkReadingAtoms(b *testing.B) {
+	for _, data := range atomTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				data.Data.Decode()
+			}
+		})
+	}
+}
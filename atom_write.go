@@ -0,0 +1,30 @@
+package erlgo
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// maxAtomLength is the maximum number of characters in an atom.
+const maxAtomLength = 255
+
+func encodeAtom(buf *bytes.Buffer, a Atom) error {
+	if !utf8.ValidString(string(a)) {
+		return fmt.Errorf("%q is not a valid utf8 atom", string(a))
+	} else if utf8.RuneCountInString(string(a)) > maxAtomLength {
+		return fmt.Errorf("%q is longer than %v characters", string(a), maxAtomLength)
+	}
+
+	if len(a) <= math.MaxUint8 {
+		buf.WriteByte(smallAtomUtf8Ext)
+		buf.WriteByte(byte(len(a)))
+	} else {
+		buf.WriteByte(atomUtf8Ext)
+		writeUint16(buf, uint16(len(a)))
+	}
+	buf.WriteString(string(a))
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"strings"
+	"testing"
+)
+
+var atomWriteTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect []byte
+}{
+	{"ok", erlgo.Atom("ok"), []byte{131, 119, 2, 111, 107}},
+	{"empty atom", erlgo.Atom(""), []byte{131, 119, 0}},
+	{"ö", erlgo.Atom("ö"), []byte{131, 119, 2, 195, 182}},
+	{"255 times 😀", erlgo.Atom(strings.Repeat("😀", 255)), append([]byte{131, 118, 3, 252}, bytes.Repeat([]byte{240, 159, 152, 128}, 255)...)},
+}
+
+func TestWritingAtoms(t *testing.T) {
+	for _, test := range atomWriteTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil && !bytes.Equal(data, test.Expect) {
+				t.Errorf(`%#v encoded into %v, expected %v.`, test.Term, data, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %v.`, test.Term, err, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkWritingAtoms(b *testing.B) {
+	for _, data := range atomWriteTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Encode(data.Term)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package erlgo
+
+import (
+	"time"
+)
+
+const bertTag Atom = "bert"
+
+// ToBERT translates t into the BERT conventions, which represent some terms
+// as tuples tagged with `bert`. The empty list becomes {bert, nil}, the
+// booleans become {bert, true} and {bert, false}, and maps become
+// {bert, dict, [{Key, Value}]}. Elements of tuples, lists and maps are
+// translated as well.
+func ToBERT(t Term) Term {
+	switch v := t.(type) {
+	case Nil:
+		return Tuple{bertTag, Atom("nil")}
+	case Atom:
+		if v.IsBool() {
+			return Tuple{bertTag, v}
+		}
+		return v
+	case Tuple:
+		return Tuple(mapTerms(v, ToBERT))
+	case Cons:
+		elems, tail := v.Elements()
+		if _, ok := tail.(Nil); !ok {
+			tail = ToBERT(tail)
+		}
+		return newListWithTail(mapTerms(elems, ToBERT), tail)
+	case Map:
+		pairs := v.SortedPairs()
+		elems := make([]Term, len(pairs))
+		for i, p := range pairs {
+			elems[i] = Tuple{ToBERT(p.Key), ToBERT(p.Value)}
+		}
+		return Tuple{bertTag, Atom("dict"), NewListFromTerms(elems)}
+	default:
+		return t
+	}
+}
+
+// FromBERT reverses ToBERT. BERT times and regular expressions have no term
+// of their own and stay as they are, see BERTTime and BERTRegex.
+func FromBERT(t Term) Term {
+	switch v := t.(type) {
+	case Tuple:
+		if len(v) >= 2 && v[0] == bertTag {
+			if complex, ok := fromBERTComplex(v); ok {
+				return complex
+			}
+			return v
+		}
+		return Tuple(mapTerms(v, FromBERT))
+	case Cons:
+		elems, tail := v.Elements()
+		return newListWithTail(mapTerms(elems, FromBERT), FromBERT(tail))
+	default:
+		return t
+	}
+}
+
+func fromBERTComplex(t Tuple) (Term, bool) {
+	switch t[1] {
+	case Atom("nil"):
+		return Nil{}, len(t) == 2
+	case True, False:
+		return t[1], len(t) == 2
+	case Atom("dict"):
+		if len(t) != 3 {
+			return nil, false
+		}
+		list, ok := t[2].(List)
+		if !ok {
+			return nil, false
+		}
+		elems, err := list.ToSlice()
+		if err != nil {
+			return nil, false
+		}
+
+		mb := newMapBuilder(len(elems))
+		for _, elem := range elems {
+			kv, ok := elem.(Tuple)
+			if !ok || len(kv) != 2 {
+				return nil, false
+			}
+			mb.put(FromBERT(kv[0]), FromBERT(kv[1]))
+		}
+		return mb.result(), true
+	default:
+		return nil, false
+	}
+}
+
+func mapTerms(terms []Term, f func(Term) Term) []Term {
+	result := make([]Term, len(terms))
+	for i, t := range terms {
+		result[i] = f(t)
+	}
+	return result
+}
+
+// BERTTime returns the BERT time {bert, time, MegaSecs, Secs, MicroSecs}.
+func BERTTime(t time.Time) Tuple {
+	micros := t.UnixNano() / 1000
+	return Tuple{bertTag, Atom("time"),
+		Int64(micros / 1e12), Int64(micros / 1e6 % 1e6), Int64(micros % 1e6)}
+}
+
+// ParseBERTTime returns the time of a BERT time tuple.
+func ParseBERTTime(t Term) (time.Time, bool) {
+	tuple, ok := t.(Tuple)
+	if !ok || len(tuple) != 5 || tuple[0] != bertTag || tuple[1] != Atom("time") {
+		return time.Time{}, false
+	}
+
+	var parts [3]int64
+	for i := range parts {
+		n, ok := tuple[i+2].(Int64)
+		if !ok {
+			return time.Time{}, false
+		}
+		parts[i] = int64(n)
+	}
+
+	return time.Unix(parts[0]*1e6+parts[1], parts[2]*1e3), true
+}
+
+// BERTRegex returns the BERT regular expression {bert, regex, Source,
+// Options}, with options like `caseless` or `multiline` as used by the re
+// module.
+func BERTRegex(source string, options ...Term) Tuple {
+	return Tuple{bertTag, Atom("regex"), Binary(source), NewListFromTerms(options)}
+}
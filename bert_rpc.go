@@ -0,0 +1,304 @@
+package erlgo
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+)
+
+// BERTError is an error returned by a BERT-RPC server, sent as
+// {error, {Type, Code, Class, Detail, Backtrace}}. Type is one of `protocol`,
+// `server`, `user` or `proxy`.
+type BERTError struct {
+	Type      Atom
+	Code      int64
+	Class     string
+	Detail    string
+	Backtrace []string
+}
+
+func (e *BERTError) Error() string {
+	return fmt.Sprintf("erlgo: bert-rpc %v error %d: %v: %v", e.Type, e.Code, e.Class, e.Detail)
+}
+
+func (e *BERTError) term() Term {
+	backtrace := make([]Term, len(e.Backtrace))
+	for i, line := range e.Backtrace {
+		backtrace[i] = Binary(line)
+	}
+
+	return Tuple{Atom("error"), Tuple{e.Type, Int64(e.Code), Binary(e.Class), Binary(e.Detail), NewListFromTerms(backtrace)}}
+}
+
+func bertErrorFromTerm(t Term) (*BERTError, bool) {
+	info, ok := t.(Tuple)
+	if !ok || len(info) != 5 {
+		return nil, false
+	}
+
+	e := &BERTError{}
+	if e.Type, ok = info[0].(Atom); !ok {
+		return nil, false
+	}
+	if code, ok := info[1].(Int64); ok {
+		e.Code = int64(code)
+	}
+	e.Class, _ = termToString(info[2])
+	e.Detail, _ = termToString(info[3])
+
+	if list, ok := info[4].(List); ok {
+		lines, _ := list.ToSlice()
+		for _, line := range lines {
+			if s, ok := termToString(line); ok {
+				e.Backtrace = append(e.Backtrace, s)
+			}
+		}
+	}
+
+	return e, true
+}
+
+// BERT-RPC protocol error codes.
+const (
+	bertProtocolHeader = 1
+	bertProtocolData   = 2
+)
+
+// BERTClient calls functions on a BERT-RPC server. Requests are sent one at
+// a time, it is safe to be used concurrently.
+type BERTClient struct {
+	conn io.ReadWriteCloser
+	r    *PacketReader
+	w    *PacketWriter
+	mu   sync.Mutex
+}
+
+// DialBERT connects to the BERT-RPC server at addr.
+func DialBERT(network, addr string) (*BERTClient, error) {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewBERTClient(conn), nil
+}
+
+// NewBERTClient returns a client sending requests over conn.
+func NewBERTClient(conn io.ReadWriteCloser) *BERTClient {
+	return &BERTClient{conn: conn, r: NewPacketReader(conn, 4), w: NewPacketWriter(conn, 4)}
+}
+
+// Call calls mod:fun with args and returns the result. Arguments and the
+// result are translated with ToBERT and FromBERT. Errors reported by the
+// server are returned as *BERTError.
+func (c *BERTClient) Call(mod, fun Atom, args ...Term) (Term, error) {
+	reply, err := c.request(Atom("call"), mod, fun, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply, ok := reply.(Tuple); ok && len(reply) == 2 && reply[0] == Atom("reply") {
+		return FromBERT(reply[1]), nil
+	}
+	return nil, fmt.Errorf("erlgo: unexpected bert-rpc reply %v", reply)
+}
+
+// Cast calls mod:fun with args without waiting for the result.
+func (c *BERTClient) Cast(mod, fun Atom, args ...Term) error {
+	reply, err := c.request(Atom("cast"), mod, fun, args)
+	if err != nil {
+		return err
+	}
+
+	if reply, ok := reply.(Tuple); ok && len(reply) == 1 && reply[0] == Atom("noreply") {
+		return nil
+	}
+	return fmt.Errorf("erlgo: unexpected bert-rpc reply %v", reply)
+}
+
+// Info sends an {info, Command, Options} packet, which applies to the next
+// request, like {info, callback, [{service, S}, {mfa, M, F, A}]}.
+func (c *BERTClient) Info(command Atom, options ...Term) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.w.Encode(Tuple{Atom("info"), command, NewListFromTerms(mapTerms(options, ToBERT))})
+}
+
+func (c *BERTClient) request(kind, mod, fun Atom, args []Term) (Term, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	request := Tuple{kind, mod, fun, NewListFromTerms(mapTerms(args, ToBERT))}
+	if err := c.w.Encode(request); err != nil {
+		return nil, err
+	}
+
+	reply, err := c.r.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if reply, ok := reply.(Tuple); ok && len(reply) == 2 && reply[0] == Atom("error") {
+		if e, ok := bertErrorFromTerm(reply[1]); ok {
+			return nil, e
+		}
+	}
+	return reply, nil
+}
+
+// Close closes the connection to the server.
+func (c *BERTClient) Close() error {
+	return c.conn.Close()
+}
+
+// BERTServer answers BERT-RPC requests. Handler is called with the module,
+// function and arguments of calls and casts, translated with FromBERT. The
+// result of calls is sent back as the reply. An error returned by Handler is
+// sent as it is if it is a *BERTError, and as a user error otherwise. A panic
+// in Handler is sent as a server error. Info packets are ignored.
+type BERTServer struct {
+	Handler func(mod, fun Atom, args []Term) (Term, error)
+
+	// MaxPacketSize limits the size of requests, a connection sending a larger
+	// one is closed. It defaults to 16 MiB.
+	MaxPacketSize int
+
+	// Options limit the decoding of requests. The limits left at zero default
+	// to those of defaultBERTOptions, requests come from untrusted clients.
+	Options DecoderOptions
+}
+
+const defaultBERTPacketSize = 16 << 20
+
+var defaultBERTOptions = DecoderOptions{
+	MaxDepth:            256,
+	MaxTerms:            1 << 20,
+	MaxBigIntBytes:      1024,
+	MaxBinarySize:       defaultBERTPacketSize,
+	MaxDecompressedSize: defaultBERTPacketSize,
+}
+
+func (s *BERTServer) options() DecoderOptions {
+	opts := s.Options
+	opts.MaxDepth = orDefault(opts.MaxDepth, defaultBERTOptions.MaxDepth)
+	opts.MaxTerms = orDefault(opts.MaxTerms, defaultBERTOptions.MaxTerms)
+	opts.MaxBigIntBytes = orDefault(opts.MaxBigIntBytes, defaultBERTOptions.MaxBigIntBytes)
+	opts.MaxBinarySize = orDefault(opts.MaxBinarySize, defaultBERTOptions.MaxBinarySize)
+	opts.MaxDecompressedSize = orDefault(opts.MaxDecompressedSize, defaultBERTOptions.MaxDecompressedSize)
+	return opts
+}
+
+func orDefault(value, def int) int {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// Serve accepts connections on l and serves each of them in its own
+// goroutine, until accepting fails.
+func (s *BERTServer) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			defer conn.Close()
+			s.ServeConn(conn)
+		}()
+	}
+}
+
+// ServeConn answers the requests read from rw until it ends or the transport
+// fails. A clean end of the input is not an error.
+func (s *BERTServer) ServeConn(rw io.ReadWriter) error {
+	in, out := NewPacketReader(rw, 4), NewPacketWriter(rw, 4)
+	in.SetMaxSize(orDefault(s.MaxPacketSize, defaultBERTPacketSize))
+	opts := s.options()
+
+	for {
+		data, err := in.ReadPacket()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		reply, cast := s.handle(data, opts)
+		if reply == nil {
+			continue
+		}
+		if err := out.Encode(reply); err != nil {
+			return err
+		}
+		if cast != nil {
+			cast()
+		}
+	}
+}
+
+// handle returns the reply to a request, or nil for info packets. Casts are
+// returned to be run after the reply is sent.
+func (s *BERTServer) handle(data []byte, opts DecoderOptions) (Term, func()) {
+	request, err := FromBytes(data).WithOptions(opts).Decode()
+	if err != nil {
+		return (&BERTError{Type: "protocol", Code: bertProtocolData, Class: "ProtocolError", Detail: err.Error()}).term(), nil
+	}
+
+	tuple, ok := request.(Tuple)
+	if ok && len(tuple) == 3 && tuple[0] == Atom("info") {
+		return nil, nil
+	}
+
+	var mod, fun Atom
+	var args []Term
+	if ok && len(tuple) == 4 {
+		mod, _ = tuple[1].(Atom)
+		fun, _ = tuple[2].(Atom)
+		if list, isList := FromBERT(tuple[3]).(List); isList {
+			args, err = list.ToSlice()
+		}
+	}
+	badRequest := &BERTError{Type: "protocol", Code: bertProtocolHeader, Class: "ProtocolError", Detail: fmt.Sprintf("bad request %v", request)}
+	if !ok || len(tuple) != 4 || mod == "" || fun == "" || args == nil || err != nil {
+		return badRequest.term(), nil
+	}
+
+	switch tuple[0] {
+	case Atom("call"):
+		result, err := s.call(mod, fun, args)
+		if err != nil {
+			return handlerError(err).term(), nil
+		}
+		if result == nil {
+			result = Nil{}
+		}
+		return Tuple{Atom("reply"), ToBERT(result)}, nil
+	case Atom("cast"):
+		return Tuple{Atom("noreply")}, func() { s.call(mod, fun, args) }
+	default:
+		return badRequest.term(), nil
+	}
+}
+
+// call runs Handler, turning a panic into a server error.
+func (s *BERTServer) call(mod, fun Atom, args []Term) (result Term, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, &BERTError{Type: "server", Class: "ServerError", Detail: fmt.Sprintf("handler panicked: %v", r)}
+		}
+	}()
+
+	return s.Handler(mod, fun, args)
+}
+
+func handlerError(err error) *BERTError {
+	if e, ok := err.(*BERTError); ok {
+		return e
+	}
+	return &BERTError{Type: "user", Class: strings.TrimPrefix(fmt.Sprintf("%T", err), "*"), Detail: err.Error()}
+}
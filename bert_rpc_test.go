@@ -0,0 +1,240 @@
+package erlgo_test
+
+import (
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+type bertCalculator struct {
+	mu    sync.Mutex
+	casts []erlgo.Term
+	done  chan struct{}
+}
+
+func (c *bertCalculator) handle(mod, fun erlgo.Atom, args []erlgo.Term) (erlgo.Term, error) {
+	if mod != "calc" {
+		return nil, &erlgo.BERTError{Type: "server", Code: 1, Class: "ServerError", Detail: "no such module"}
+	}
+
+	switch fun {
+	case "add":
+		sum := int64(0)
+		for _, arg := range args {
+			n, ok := arg.(erlgo.Int64)
+			if !ok {
+				return nil, errors.New("not a number")
+			}
+			sum += int64(n)
+		}
+		return erlgo.Int64(sum), nil
+	case "echo":
+		return erlgo.Tuple(args), nil
+	case "crash":
+		panic("crashed")
+	case "log":
+		c.mu.Lock()
+		c.casts = append(c.casts, erlgo.Tuple(args))
+		c.mu.Unlock()
+		close(c.done)
+		return nil, nil
+	default:
+		return nil, &erlgo.BERTError{Type: "server", Code: 2, Class: "ServerError", Detail: "no such function"}
+	}
+}
+
+func startBERTServer(t *testing.T) (*bertCalculator, *erlgo.BERTClient, func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(`listening failed with "%v".`, err)
+	}
+
+	calc := &bertCalculator{done: make(chan struct{})}
+	server := erlgo.BERTServer{Handler: calc.handle}
+	go server.Serve(ln)
+
+	client, err := erlgo.DialBERT("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf(`dialing failed with "%v".`, err)
+	}
+
+	return calc, client, func() {
+		client.Close()
+		ln.Close()
+	}
+}
+
+func TestBERTRPCCall(t *testing.T) {
+	_, client, stop := startBERTServer(t)
+	defer stop()
+
+	if result, err := client.Call("calc", "add", erlgo.Int64(1), erlgo.Int64(2)); err != nil {
+		t.Errorf(`calc:add encountered error "%v", expected 3.`, err)
+	} else if !result.Matches(erlgo.Int64(3)) {
+		t.Errorf(`calc:add returned %v, expected 3.`, result)
+	}
+
+	if err := client.Info("callback", erlgo.Tuple{erlgo.Atom("service"), erlgo.Binary("calc")}); err != nil {
+		t.Errorf(`sending info encountered error "%v".`, err)
+	}
+
+	// complex types make the round trip
+	args := []erlgo.Term{erlgo.Nil{}, erlgo.True, erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.False})}
+	if result, err := client.Call("calc", "echo", args...); err != nil {
+		t.Errorf(`calc:echo encountered error "%v", expected %v.`, err, erlgo.Tuple(args))
+	} else if !result.Matches(erlgo.Tuple(args)) {
+		t.Errorf(`calc:echo returned %v, expected %v.`, result, erlgo.Tuple(args))
+	}
+
+	if result, err := client.Call("calc", "echo"); err != nil {
+		t.Errorf(`calc:echo without arguments encountered error "%v".`, err)
+	} else if !result.Matches(erlgo.Tuple{}) {
+		t.Errorf(`calc:echo without arguments returned %v, expected {}.`, result)
+	}
+}
+
+func TestBERTRPCErrors(t *testing.T) {
+	_, client, stop := startBERTServer(t)
+	defer stop()
+
+	for _, test := range []struct {
+		Name     string
+		Mod, Fun erlgo.Atom
+		Args     []erlgo.Term
+		Type     erlgo.Atom
+		Code     int64
+	}{
+		{"no such module", "nope", "add", nil, "server", 1},
+		{"no such function", "calc", "sub", nil, "server", 2},
+		{"user error", "calc", "add", []erlgo.Term{erlgo.Atom("x")}, "user", 0},
+		{"panic", "calc", "crash", nil, "server", 0},
+	} {
+		_, err := client.Call(test.Mod, test.Fun, test.Args...)
+
+		var berr *erlgo.BERTError
+		if !errors.As(err, &berr) {
+			t.Errorf(`%v: returned error %v, expected a BERTError.`, test.Name, err)
+		} else if berr.Type != test.Type || berr.Code != test.Code {
+			t.Errorf(`%v: returned a %v error %v, expected a %v error %v.`, test.Name, berr.Type, berr.Code, test.Type, test.Code)
+		}
+	}
+
+	// the connection is still usable after errors
+	if _, err := client.Call("calc", "add"); err != nil {
+		t.Errorf(`calling after errors encountered error "%v".`, err)
+	}
+}
+
+func TestBERTRPCLargeDict(t *testing.T) {
+	_, client, stop := startBERTServer(t)
+	defer stop()
+
+	pairs := make([]erlgo.Pair, 40000)
+	for i := range pairs {
+		pairs[i] = erlgo.Pair{Key: erlgo.Int64(i), Value: erlgo.Int64(i)}
+	}
+	dict := erlgo.NewMap(pairs...)
+
+	start := time.Now()
+	if result, err := client.Call("calc", "echo", dict); err != nil {
+		t.Errorf(`calc:echo encountered error "%v".`, err)
+	} else if !result.Matches(erlgo.Tuple{dict}) {
+		t.Errorf(`calc:echo returned a different dict.`)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf(`calc:echo of %v pairs took %v.`, len(pairs), elapsed)
+	}
+}
+
+func TestBERTRPCCast(t *testing.T) {
+	calc, client, stop := startBERTServer(t)
+	defer stop()
+
+	if err := client.Cast("calc", "log", erlgo.Binary("hello")); err != nil {
+		t.Fatalf(`casting encountered error "%v".`, err)
+	}
+	<-calc.done
+
+	calc.mu.Lock()
+	defer calc.mu.Unlock()
+	if len(calc.casts) != 1 || !calc.casts[0].Matches(erlgo.Tuple{erlgo.Binary("hello")}) {
+		t.Errorf(`the cast was handled as %v, expected {<<"hello">>}.`, calc.casts)
+	}
+}
+
+func TestBERTRPCBadRequests(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go func() {
+		defer server.Close()
+		(&erlgo.BERTServer{Handler: (&bertCalculator{}).handle}).ServeConn(server)
+	}()
+
+	w, r := erlgo.NewPacketWriter(client, 4), erlgo.NewPacketReader(client, 4)
+	for _, test := range []struct {
+		Name   string
+		Packet []byte
+		Code   int64
+	}{
+		{"not a term", []byte{131, 255}, 2},
+		{"not a request", []byte{131, 100, 0, 2, 'h', 'i'}, 1},
+		{"too deep", deepTuple(1000), 2},
+	} {
+		if err := w.WritePacket(test.Packet); err != nil {
+			t.Fatalf(`%v: writing encountered error "%v".`, test.Name, err)
+		}
+
+		reply, err := r.Decode()
+		if err != nil {
+			t.Fatalf(`%v: reading the reply encountered error "%v".`, test.Name, err)
+		} else if tuple, ok := reply.(erlgo.Tuple); !ok || len(tuple) != 2 || !tuple[0].Matches(erlgo.Atom("error")) {
+			t.Errorf(`%v: replied %v, expected a protocol error %v.`, test.Name, reply, test.Code)
+		} else if info, ok := tuple[1].(erlgo.Tuple); !ok || len(info) != 5 || !info[0].Matches(erlgo.Atom("protocol")) || !info[1].Matches(erlgo.Int64(test.Code)) {
+			t.Errorf(`%v: replied %v, expected a protocol error %v.`, test.Name, reply, test.Code)
+		}
+	}
+}
+
+// deepTuple returns the encoding of a term nested in depth tuples.
+func deepTuple(depth int) []byte {
+	data := []byte{131}
+	for i := 0; i < depth; i++ {
+		data = append(data, 104, 1)
+	}
+	return append(data, 106)
+}
+
+func TestBERTRPCWithoutHandler(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	go (&erlgo.BERTServer{}).ServeConn(server)
+
+	c := erlgo.NewBERTClient(client)
+	defer c.Close()
+
+	var berr *erlgo.BERTError
+	if _, err := c.Call("calc", "add"); !errors.As(err, &berr) || berr.Type != "server" {
+		t.Errorf(`calling without a handler returned error %v, expected a server error.`, err)
+	}
+}
+
+func TestBERTRPCPacketSize(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		defer server.Close()
+		done <- (&erlgo.BERTServer{Handler: (&bertCalculator{}).handle, MaxPacketSize: 8}).ServeConn(server)
+	}()
+
+	// the server stops reading after the header, which fails the write
+	erlgo.NewPacketWriter(client, 4).WritePacket(make([]byte, 9))
+	if err := <-done; err == nil {
+		t.Errorf(`serving a packet of 9 bytes succeeded, expected an error.`)
+	}
+}
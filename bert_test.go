@@ -0,0 +1,81 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, text string) erlgo.Term {
+	term, err := erlgo.ParseTerm(text)
+	if err != nil {
+		t.Fatalf(`parsing %v failed with "%v".`, text, err)
+	}
+	return term
+}
+
+var bertTestTable = []struct {
+	Name string
+	Term string
+	BERT string
+}{
+	{"nil", "[]", "{bert, nil}"},
+	{"true", "true", "{bert, true}"},
+	{"false", "false", "{bert, false}"},
+	{"atom", "ok", "ok"},
+	{"dict", "#{a => 1, b => []}", "{bert, dict, [{a, 1}, {b, {bert, nil}}]}"},
+	{"nested", "{ok, [true, #{}]}", "{ok, [{bert, true}, {bert, dict, []}]}"},
+	{"improper list", "[1 | true]", "[1 | {bert, true}]"},
+	{"string", `"abc"`, `"abc"`},
+}
+
+func TestToBERT(t *testing.T) {
+	for _, test := range bertTestTable {
+		term, expect := mustParse(t, test.Term), mustParse(t, test.BERT)
+		if bert := erlgo.ToBERT(term); !bert.Matches(expect) {
+			t.Errorf(`%v translated into %v, expected %v.`, test.Name, bert, expect)
+		}
+	}
+}
+
+func TestFromBERT(t *testing.T) {
+	for _, test := range bertTestTable {
+		bert, expect := mustParse(t, test.BERT), mustParse(t, test.Term)
+		if term := erlgo.FromBERT(bert); !term.Matches(expect) {
+			t.Errorf(`%v translated back into %v, expected %v.`, test.Name, term, expect)
+		}
+	}
+
+	// unknown and malformed complex types are kept
+	for _, text := range []string{"{bert, time, 1, 2, 3}", "{bert, dict, [a]}", "{bert, nil, extra}"} {
+		bert := mustParse(t, text)
+		if term := erlgo.FromBERT(bert); !term.Matches(bert) {
+			t.Errorf(`%v translated into %v, expected it unchanged.`, text, term)
+		}
+	}
+}
+
+func TestBERTTime(t *testing.T) {
+	now := time.Unix(1255295581, 446228000)
+	expect := mustParse(t, "{bert, time, 1255, 295581, 446228}")
+
+	bert := erlgo.BERTTime(now)
+	if !bert.Matches(expect) {
+		t.Errorf(`time translated into %v, expected %v.`, bert, expect)
+	}
+
+	if back, ok := erlgo.ParseBERTTime(bert); !ok || !back.Equal(now) {
+		t.Errorf(`%v parsed into %v, expected %v.`, bert, back, now)
+	}
+
+	if _, ok := erlgo.ParseBERTTime(mustParse(t, "{bert, time, 1, 2}")); ok {
+		t.Errorf(`a time without microseconds parsed successfully.`)
+	}
+}
+
+func TestBERTRegex(t *testing.T) {
+	expect := mustParse(t, `{bert, regex, <<"^c(a*)t$">>, [caseless]}`)
+	if bert := erlgo.BERTRegex("^c(a*)t$", erlgo.Atom("caseless")); !bert.Matches(expect) {
+		t.Errorf(`regex translated into %v, expected %v.`, bert, expect)
+	}
+}
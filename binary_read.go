@@ -0,0 +1,132 @@
+package erlgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+type Binary []byte
+
+func (bin Binary) IsInteger() bool {
+	return false
+}
+
+func (bin Binary) IsList() bool { return false }
+
+func (bin Binary) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+func (bin Binary) Matches(other Term) bool {
+	switch o := other.(type) {
+	case Binary:
+		return bytes.Equal(bin, o)
+	case BitString:
+		return o.Matches(bin)
+	default:
+		return false
+	}
+}
+
+// BitString is a sequence of bits whose length is not necessarily divisible
+// by 8. Bits is the number of significant bits in the last byte, counted from
+// the most significant one, and is between 1 and 8 for non-empty Bytes.
+type BitString struct {
+	Bytes []byte
+	Bits  uint8
+}
+
+// BitLen returns the total number of bits in the bitstring.
+func (bs BitString) BitLen() int {
+	if len(bs.Bytes) == 0 {
+		return 0
+	}
+	return (len(bs.Bytes)-1)*8 + int(bs.Bits)
+}
+
+func (bs BitString) IsInteger() bool {
+	return false
+}
+
+func (bs BitString) IsList() bool { return false }
+
+func (bs BitString) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+func (bs BitString) Matches(other Term) bool {
+	switch o := other.(type) {
+	case BitString:
+		if bs.BitLen() != o.BitLen() {
+			return false
+		}
+		if len(bs.Bytes) == 0 {
+			return true
+		}
+		last := len(bs.Bytes) - 1
+		mask := byte(0xff << (8 - bs.Bits))
+		return bytes.Equal(bs.Bytes[:last], o.Bytes[:last]) && bs.Bytes[last]&mask == o.Bytes[last]&mask
+	case Binary:
+		return bs.Matches(BitString{Bytes: o, Bits: 8})
+	default:
+		return false
+	}
+}
+
+func decodeBinary(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != binaryExt {
+		return nil, fmt.Errorf("%v is not tagging a binary", tag)
+	}
+
+	length, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.state.checkBinarySize(uint32(length)); err != nil {
+		return nil, err
+	}
+
+	data, err := readBytes(b, int(uint32(length)))
+	if err != nil {
+		return nil, err
+	}
+
+	return Binary(data), nil
+}
+
+func decodeBitBinary(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != bitBinaryExt {
+		return nil, fmt.Errorf("%v is not tagging a bit binary", tag)
+	}
+
+	length, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	}
+
+	bits, err := b.bs.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if length == 0 && bits != 0 || length != 0 && (bits < 1 || bits > 8) {
+		return nil, fmt.Errorf("%v is not a valid number of bits in the last byte", bits)
+	}
+
+	if err := b.state.checkBinarySize(uint32(length)); err != nil {
+		return nil, err
+	}
+
+	data, err := readBytes(b, int(uint32(length)))
+	if err != nil {
+		return nil, err
+	}
+
+	return BitString{Bytes: data, Bits: bits}, nil
+}
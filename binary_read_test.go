@@ -0,0 +1,78 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var binaryTestTable = []struct {
+	Name   string
+	Data   erlgo.ErlExtBinary
+	Expect erlgo.Term
+}{
+	{"<<>>", erlgo.FromBytes([]byte{131, 109, 0, 0, 0, 0}), erlgo.Binary{}},
+	{"<<\"abc\">>", erlgo.FromBytes([]byte{131, 109, 0, 0, 0, 3, 97, 98, 99}), erlgo.Binary("abc")},
+	{"<<0, 255>>", erlgo.FromBytes([]byte{131, 109, 0, 0, 0, 2, 0, 255}), erlgo.Binary{0, 255}},
+	{"<<1:1>>", erlgo.FromBytes([]byte{131, 77, 0, 0, 0, 1, 1, 128}), erlgo.BitString{Bytes: []byte{128}, Bits: 1}},
+	{"<<255, 5:3>>", erlgo.FromBytes([]byte{131, 77, 0, 0, 0, 2, 3, 255, 160}), erlgo.BitString{Bytes: []byte{255, 160}, Bits: 3}},
+	{"<<1, 2>> (bit binary)", erlgo.FromBytes([]byte{131, 77, 0, 0, 0, 2, 8, 1, 2}), erlgo.Binary{1, 2}},
+}
+
+func TestReadingBinaries(t *testing.T) {
+	for _, test := range binaryTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if val, err := test.Data.Decode(); err == nil && !val.Matches(test.Expect) {
+				t.Errorf(`%#v parsed into %#v, expected %#v."`, test.Data, val, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected value %#v.`, test.Data, err, test.Expect)
+			}
+		})
+	}
+}
+
+func TestReadingInvalidBitBinaries(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Data erlgo.ErlExtBinary
+	}{
+		{"zero bits", erlgo.FromBytes([]byte{131, 77, 0, 0, 0, 1, 0, 128})},
+		{"nine bits", erlgo.FromBytes([]byte{131, 77, 0, 0, 0, 1, 9, 128})},
+		{"truncated", erlgo.FromBytes([]byte{131, 77, 0, 0, 0, 2, 3, 255})},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if val, err := test.Data.Decode(); err == nil {
+				t.Errorf(`%#v parsed into %#v, expected an error.`, test.Data, val)
+			}
+		})
+	}
+}
+
+func TestBitStringMatches(t *testing.T) {
+	bits := erlgo.BitString{Bytes: []byte{255, 160}, Bits: 3}
+	for _, test := range []struct {
+		Name   string
+		Other  erlgo.Term
+		Expect bool
+	}{
+		{"same", erlgo.BitString{Bytes: []byte{255, 160}, Bits: 3}, true},
+		{"padding differs", erlgo.BitString{Bytes: []byte{255, 191}, Bits: 3}, true},
+		{"bits differ", erlgo.BitString{Bytes: []byte{255, 160}, Bits: 4}, false},
+		{"binary", erlgo.Binary{255, 160}, false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if bits.Matches(test.Other) != test.Expect {
+				t.Errorf(`%#v.Matches(%#v) returned %v, expected %v.`, bits, test.Other, !test.Expect, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkReadingBinaries(b *testing.B) {
+	for _, data := range binaryTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				data.Data.Decode()
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package erlgo
+
+import (
+	"bytes"
+	"fmt"
+)
+
+func encodeBinary(buf *bytes.Buffer, bin Binary) error {
+	buf.WriteByte(binaryExt)
+	writeUint32(buf, uint32(len(bin)))
+	buf.Write(bin)
+
+	return nil
+}
+
+// encodeBitString falls back to BINARY_EXT when all bits of the last byte are
+// used, and clears the unused bits otherwise.
+func encodeBitString(buf *bytes.Buffer, bs BitString) error {
+	if len(bs.Bytes) == 0 || bs.Bits == 8 {
+		return encodeBinary(buf, bs.Bytes)
+	} else if bs.Bits < 1 || bs.Bits > 8 {
+		return fmt.Errorf("%v is not a valid number of bits in the last byte", bs.Bits)
+	}
+
+	last := len(bs.Bytes) - 1
+
+	buf.WriteByte(bitBinaryExt)
+	writeUint32(buf, uint32(len(bs.Bytes)))
+	buf.WriteByte(bs.Bits)
+	buf.Write(bs.Bytes[:last])
+	buf.WriteByte(bs.Bytes[last] & byte(0xff<<(8-bs.Bits)))
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var binaryWriteTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect []byte
+}{
+	{"<<>>", erlgo.Binary{}, []byte{131, 109, 0, 0, 0, 0}},
+	{"<<\"abc\">>", erlgo.Binary("abc"), []byte{131, 109, 0, 0, 0, 3, 97, 98, 99}},
+	{"<<255, 5:3>>", erlgo.BitString{Bytes: []byte{255, 191}, Bits: 3}, []byte{131, 77, 0, 0, 0, 2, 3, 255, 160}},
+	{"<<1, 2>> (bitstring)", erlgo.BitString{Bytes: []byte{1, 2}, Bits: 8}, []byte{131, 109, 0, 0, 0, 2, 1, 2}},
+	{"<<>> (bitstring)", erlgo.BitString{}, []byte{131, 109, 0, 0, 0, 0}},
+}
+
+func TestWritingBinaries(t *testing.T) {
+	for _, test := range binaryWriteTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil && !bytes.Equal(data, test.Expect) {
+				t.Errorf(`%#v encoded into %v, expected %v.`, test.Term, data, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %v.`, test.Term, err, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkWritingBinaries(b *testing.B) {
+	for _, data := range binaryWriteTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Encode(data.Term)
+			}
+		})
+	}
+}
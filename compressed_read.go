@@ -0,0 +1,78 @@
+package erlgo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// CompressedSizeError reports a compressed term which does not inflate to the
+// size declared in front of it.
+type CompressedSizeError struct {
+	Size uint32
+
+	// Inflated is the number of bytes found, decompression stops at Size+1.
+	Inflated int
+}
+
+func (e *CompressedSizeError) Error() string {
+	if e.Inflated > int(e.Size) {
+		return fmt.Sprintf("compressed term inflates to more than the declared %v bytes", e.Size)
+	}
+
+	return fmt.Sprintf("compressed term inflates to %v bytes instead of the declared %v", e.Inflated, e.Size)
+}
+
+// decodeCompressed inflates at most the declared number of bytes and decodes
+// the term from them. The zlib stream gets consumed up to its checksum, so
+// that a stream of terms stays aligned.
+func decodeCompressed(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != compressed {
+		return nil, fmt.Errorf("%v is not tagging a compressed term", tag)
+	}
+
+	sizeBytes, err := readBytes(b, 4)
+	if err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(sizeBytes)
+	if err := b.state.checkDecompressedSize(size); err != nil {
+		return nil, err
+	}
+
+	// the bufio.Reader is an io.ByteReader, so zlib does not read past the
+	// end of the compressed data
+	zr, err := zlib.NewReader(b.bs)
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	inflated, err := ioutil.ReadAll(io.LimitReader(zr, int64(size)+1))
+	if err != nil {
+		return nil, err
+	} else if len(inflated) != int(size) {
+		return nil, &CompressedSizeError{Size: size, Inflated: len(inflated)}
+	}
+
+	inner := newErlExtBinary(bytes.NewReader(inflated))
+	inner.refs, inner.state = b.refs, b.state
+
+	term, err := decodeRemaining(inner)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := inner.bs.Peek(1); err != io.EOF {
+		return nil, fmt.Errorf("compressed term has data after the term")
+	}
+
+	return term, nil
+}
@@ -0,0 +1,128 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"io"
+	"testing"
+)
+
+// the zlib streams of "aaaaaaaaaaaaaaaaaaaa" as string and of 1000 zero bytes
+// as binary
+var (
+	compressedString = []byte{120, 156, 203, 102, 16, 73, 196, 2, 0, 90, 234, 8, 20}
+	compressedBinary = []byte{120, 156, 203, 101, 96, 96, 126, 193, 48, 10, 70, 193, 40, 24, 246, 0, 0, 71, 7, 1, 89}
+)
+
+func repeatedChars(c byte, n int) erlgo.List {
+	terms := make([]erlgo.Term, n)
+	for i := range terms {
+		terms[i] = erlgo.Int64(c)
+	}
+	return erlgo.NewListFromTerms(terms)
+}
+
+func compressedData(size uint32, stream []byte) []byte {
+	return append([]byte{131, 80, byte(size >> 24), byte(size >> 16), byte(size >> 8), byte(size)}, stream...)
+}
+
+var compressedTestTable = []struct {
+	Name   string
+	Data   erlgo.ErlExtBinary
+	Expect erlgo.Term
+}{
+	{"string", erlgo.FromBytes(compressedData(23, compressedString)), repeatedChars('a', 20)},
+	{"binary", erlgo.FromBytes(compressedData(1005, compressedBinary)), erlgo.Binary(make([]byte, 1000))},
+}
+
+func TestReadingCompressed(t *testing.T) {
+	for _, test := range compressedTestTable {
+		if val, err := test.Data.Decode(); err != nil {
+			t.Errorf(`%v encountered error "%v", expected %#v.`, test.Name, err, test.Expect)
+		} else if !val.Matches(test.Expect) {
+			t.Errorf(`%v parsed into %#v, expected %#v.`, test.Name, val, test.Expect)
+		}
+	}
+}
+
+func BenchmarkReadingCompressed(b *testing.B) {
+	for _, data := range compressedTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				data.Data.Decode()
+			}
+		})
+	}
+}
+
+func TestCompressedSizeMismatch(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Size     uint32
+		Inflated int
+	}{
+		{"declared too small", 1004, 1005},
+		{"declared too large", 1006, 1005},
+		{"declared empty", 0, 1},
+	} {
+		var sizeErr *erlgo.CompressedSizeError
+		if _, err := erlgo.FromBytes(compressedData(test.Size, compressedBinary)).Decode(); !errors.As(err, &sizeErr) {
+			t.Errorf(`%v encountered error "%v", expected a *CompressedSizeError.`, test.Name, err)
+		} else if sizeErr.Size != test.Size || sizeErr.Inflated != test.Inflated {
+			t.Errorf(`%v reported %#v, expected size %v and %v inflated bytes.`, test.Name, sizeErr, test.Size, test.Inflated)
+		}
+	}
+}
+
+func TestCompressedErrors(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Data []byte
+	}{
+		{"no stream", compressedData(23, nil)},
+		{"truncated size", []byte{131, 80, 0, 0}},
+		{"invalid zlib header", compressedData(23, []byte{0, 0, 203, 102})},
+		{"truncated stream", compressedData(1005, compressedBinary[:10])},
+		{"corrupted checksum", compressedData(23, append(append([]byte{}, compressedString[:12]...), 0))},
+	} {
+		if val, err := erlgo.FromBytes(test.Data).Decode(); err == nil {
+			t.Errorf(`%v parsed into %#v, expected an error.`, test.Name, val)
+		}
+	}
+}
+
+func TestDecoderCompressedAlignment(t *testing.T) {
+	data := append(compressedData(1005, compressedBinary), 131, 97, 42)
+	data = append(data, compressedData(23, compressedString)...)
+
+	d := erlgo.NewDecoder(bytes.NewReader(data))
+	for _, expect := range []erlgo.Term{compressedTestTable[1].Expect, erlgo.Int64(42), compressedTestTable[0].Expect} {
+		if val, err := d.Decode(); err != nil {
+			t.Fatalf(`encountered error "%v", expected %#v.`, err, expect)
+		} else if !val.Matches(expect) {
+			t.Errorf(`decoded %#v, expected %#v.`, val, expect)
+		}
+	}
+	if val, err := d.Decode(); err != io.EOF {
+		t.Errorf(`decoded (%#v, %v) at the end, expected io.EOF.`, val, err)
+	}
+}
+
+func TestCompressedOnlyOutermost(t *testing.T) {
+	// the zlib stream of a compressed term wrapping the string above
+	doublyCompressed := []byte{120, 156, 11, 96, 96, 96, 16, 175, 152, 115, 58, 77, 192, 243, 8, 19, 67, 212, 43, 14, 17, 0, 45, 155, 5, 44}
+
+	for _, test := range []struct {
+		Name string
+		Data []byte
+	}{
+		{"in a tuple", append([]byte{131, 104, 1}, compressedData(23, compressedString)[1:]...)},
+		{"in a list", append([]byte{131, 108, 0, 0, 0, 1}, append(compressedData(23, compressedString)[1:], 106)...)},
+		{"in a compressed term", compressedData(18, doublyCompressed)},
+	} {
+		if val, err := erlgo.FromBytes(test.Data).Decode(); !errors.Is(err, erlgo.ErrUnsupportedTag) {
+			t.Errorf(`%v parsed into (%#v, %v), expected ErrUnsupportedTag.`, test.Name, val, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package erlgo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+)
+
+// SetCompression makes the Encoder compress terms with the zlib level 1 to 9,
+// like `term_to_binary(T, [{compressed, Level}])`. Level 0 turns compression
+// off, which is the default. As in Erlang, the compressed form is only written
+// if it is shorter.
+func (e *Encoder) SetCompression(level int) error {
+	if level < zlib.NoCompression || level > zlib.BestCompression {
+		return fmt.Errorf("%v is not a compression level between 0 and 9", level)
+	}
+
+	e.level = level
+	return nil
+}
+
+// EncodeCompressed is Encode with compression at the given level.
+func EncodeCompressed(t Term, level int) ([]byte, error) {
+	var buf bytes.Buffer
+
+	e := NewEncoder(&buf)
+	if err := e.SetCompression(level); err != nil {
+		return nil, err
+	}
+
+	if err := e.Encode(t); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// compressTerm compresses the encoded term following the version byte in buf,
+// and returns buf unchanged if that does not save any bytes.
+func compressTerm(buf *bytes.Buffer, level int) (*bytes.Buffer, error) {
+	term := buf.Bytes()[1:]
+
+	out := bytes.NewBuffer([]byte{131, compressed})
+	writeUint32(out, uint32(len(term)))
+
+	zw, err := zlib.NewWriterLevel(out, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(term); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	if out.Len() >= buf.Len() {
+		return buf, nil
+	}
+
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+func recordList(n int) erlgo.List {
+	terms := make([]erlgo.Term, n)
+	for i := range terms {
+		terms[i] = erlgo.Tuple{erlgo.Atom("person"), erlgo.Binary("Jane Doe"), erlgo.Int64(42)}
+	}
+	return erlgo.NewListFromTerms(terms)
+}
+
+func TestWritingCompressed(t *testing.T) {
+	records := recordList(100)
+
+	plain, err := erlgo.Encode(records)
+	if err != nil {
+		t.Fatalf(`encountered error "%v" while encoding.`, err)
+	}
+
+	for level := 1; level <= 9; level++ {
+		data, err := erlgo.EncodeCompressed(records, level)
+		if err != nil {
+			t.Fatalf(`level %v encountered error "%v".`, level, err)
+		} else if data[1] != 80 || len(data) >= len(plain) {
+			t.Errorf(`level %v wrote %v bytes with tag %v, expected a compressed term shorter than %v bytes.`, level, len(data), data[1], len(plain))
+		} else if val, err := erlgo.FromBytes(data).Decode(); err != nil {
+			t.Errorf(`level %v encountered error "%v" while decoding.`, level, err)
+		} else if !val.Matches(records) {
+			t.Errorf(`level %v parsed into %#v, expected %#v.`, level, val, records)
+		}
+	}
+}
+
+func TestWritingCompressedOnlyWhenShorter(t *testing.T) {
+	for _, level := range []int{0, 6, 9} {
+		if data, err := erlgo.EncodeCompressed(erlgo.Atom("small"), level); err != nil {
+			t.Errorf(`level %v encountered error "%v".`, level, err)
+		} else if expect := []byte{131, 119, 5, 's', 'm', 'a', 'l', 'l'}; !bytes.Equal(data, expect) {
+			t.Errorf(`level %v wrote %v, expected %v.`, level, data, expect)
+		}
+	}
+
+	if data, err := erlgo.EncodeCompressed(recordList(100), 0); err != nil {
+		t.Errorf(`level 0 encountered error "%v".`, err)
+	} else if data[1] == 80 {
+		t.Errorf(`level 0 wrote a compressed term.`)
+	}
+}
+
+func TestWritingCompressedInvalidLevel(t *testing.T) {
+	for _, level := range []int{-1, 10} {
+		if data, err := erlgo.EncodeCompressed(erlgo.Int64(1), level); err == nil {
+			t.Errorf(`level %v wrote %v, expected an error.`, level, data)
+		}
+	}
+}
+
+func TestCompressedRoundTrip(t *testing.T) {
+	for kind, table := range roundTripTables {
+		for _, test := range table {
+			t.Run(kind+"/"+test.Name, func(t *testing.T) {
+				if data, err := erlgo.EncodeCompressed(test.Expect, 9); err != nil {
+					t.Errorf(`%#v encountered error "%v" while encoding.`, test.Expect, err)
+				} else if val, err := erlgo.FromBytes(data).Decode(); err != nil {
+					t.Errorf(`%v encountered error "%v", expected value %#v.`, data, err, test.Expect)
+				} else if !val.Matches(test.Expect) {
+					t.Errorf(`%#v was encoded into %v and parsed into %#v.`, test.Expect, data, val)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkWritingCompressed(b *testing.B) {
+	records := recordList(100)
+	for i := 0; i < b.N; i++ {
+		erlgo.EncodeCompressed(records, 6)
+	}
+}
@@ -0,0 +1,74 @@
+package erlgo
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var codingComment = regexp.MustCompile(`^%.*coding\s*[:=]\s*([-a-zA-Z0-9_]+)`)
+
+// Consult reads a sequence of terms, each terminated by a dot, like
+// file:consult/1 does. The text is UTF-8, unless a comment on one of the
+// first two lines declares `coding: latin-1`.
+func Consult(r io.Reader) ([]Term, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := decodeSource(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := newParser(text)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := []Term{}
+	for p.tok.kind != tokenEOF {
+		t, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.tok.kind != tokenDot {
+			return nil, p.errorf("expected a dot after the term, found %v", p.tok)
+		} else if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		terms = append(terms, t)
+	}
+
+	return terms, nil
+}
+
+func decodeSource(data []byte) (string, error) {
+	lines := strings.SplitN(string(data), "\n", 3)
+	if len(lines) > 2 {
+		lines = lines[:2]
+	}
+	for _, line := range lines {
+		if m := codingComment.FindStringSubmatch(line); m != nil {
+			switch strings.ToLower(m[1]) {
+			case "latin-1", "latin1", "iso-8859-1":
+				return string(latin1ToRunes(data)), nil
+			case "utf-8", "utf8":
+			default:
+				return "", &ParseError{Line: 1, Column: 1, Err: errors.New("unknown coding " + m[1])}
+			}
+		}
+	}
+
+	if !utf8.Valid(data) {
+		return "", &ParseError{Line: 1, Column: 1, Err: errors.New("text is not valid utf8")}
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,89 @@
+package erlgo_test
+
+import (
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"strings"
+	"testing"
+)
+
+const appFile = `%% -*- erlang -*-
+{application, demo,
+ [{description, "A demo application"},
+  {vsn, "1.0.0"},
+  {registered, []},
+  {applications, [kernel, stdlib]},
+  {mod, {demo_app, []}},
+  {env, [{port, 8080}, {ratio, 0.5}]}]}.
+`
+
+func TestConsult(t *testing.T) {
+	terms, err := erlgo.Consult(strings.NewReader(appFile))
+	if err != nil {
+		t.Fatalf(`consulting the app file failed: %v`, err)
+	} else if len(terms) != 1 {
+		t.Fatalf(`consulted %v terms, expected 1.`, len(terms))
+	}
+
+	expect, _ := erlgo.ParseTerm(`{application, demo,
+		[{description, "A demo application"}, {vsn, "1.0.0"}, {registered, []},
+		 {applications, [kernel, stdlib]}, {mod, {demo_app, []}}, {env, [{port, 8080}, {ratio, 0.5}]}]}`)
+	if !terms[0].Matches(expect) {
+		t.Errorf(`consulted %v, expected %v.`, terms[0], expect)
+	}
+}
+
+func TestConsultTerms(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Text   string
+		Expect []erlgo.Term
+	}{
+		{"empty", "", []erlgo.Term{}},
+		{"only comments", "% nothing here\n", []erlgo.Term{}},
+		{"several terms", "{erl_opts, [debug_info]}.\n{deps, []}.", []erlgo.Term{
+			erlgo.Tuple{erlgo.Atom("erl_opts"), erlgo.NewListFromTerms([]erlgo.Term{erlgo.Atom("debug_info")})},
+			erlgo.Tuple{erlgo.Atom("deps"), erlgo.Nil{}},
+		}},
+		{"dot before comment", "a.% done", []erlgo.Term{erlgo.Atom("a")}},
+		{"latin-1", "%% coding: latin-1\n\"\xe4\".", []erlgo.Term{erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(0xe4)})}},
+		{"utf-8", "\"\xc3\xa4\".", []erlgo.Term{erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(0xe4)})}},
+	} {
+		terms, err := erlgo.Consult(strings.NewReader(test.Text))
+		if err != nil {
+			t.Errorf(`%v: encountered error "%v", expected %v.`, test.Name, err, test.Expect)
+			continue
+		} else if len(terms) != len(test.Expect) {
+			t.Errorf(`%v: consulted %v, expected %v.`, test.Name, terms, test.Expect)
+			continue
+		}
+
+		for i := range terms {
+			if !terms[i].Matches(test.Expect[i]) {
+				t.Errorf(`%v: consulted %v, expected %v.`, test.Name, terms, test.Expect)
+			}
+		}
+	}
+}
+
+func TestConsultErrors(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Text string
+		Line int
+	}{
+		{"missing dot", "{a, b}", 1},
+		{"missing dot between terms", "a.\nb\nc.", 3},
+		{"syntax error", "a.\n{b,.", 2},
+		{"invalid utf8", "\"\xe4\".", 1},
+	} {
+		_, err := erlgo.Consult(strings.NewReader(test.Text))
+
+		var perr *erlgo.ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf(`%v: returned error %v, expected a ParseError.`, test.Name, err)
+		} else if perr.Line != test.Line {
+			t.Errorf(`%v: failed on line %v, expected %v (%v).`, test.Name, perr.Line, test.Line, err)
+		}
+	}
+}
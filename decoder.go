@@ -0,0 +1,31 @@
+package erlgo
+
+import (
+	"io"
+)
+
+// Decoder reads successive terms, each prefixed with its own version byte,
+// from an input stream.
+type Decoder struct {
+	b ErlExtBinary
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{newErlExtBinary(r)}
+}
+
+// Decode reads the next term from the stream. It returns io.EOF if the stream
+// ends before a term has been started, and a *SyntaxError caused by
+// ErrTruncated if it ends in the middle of a term.
+func (d *Decoder) Decode() (Term, error) {
+	if _, err := d.b.bs.Peek(1); err != nil {
+		return nil, err
+	}
+
+	return d.b.Decode()
+}
+
+// SetOptions makes the Decoder enforce the given limits on each term.
+func (d *Decoder) SetOptions(opts DecoderOptions) {
+	d.b = d.b.WithOptions(opts)
+}
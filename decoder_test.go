@@ -0,0 +1,89 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+var decoderTerms = []erlgo.Term{
+	erlgo.Int64(1),
+	erlgo.Atom("ok"),
+	erlgo.Tuple{erlgo.Atom("error"), erlgo.Binary("reason")},
+	erlgo.NewListFromTerms([]erlgo.Term{erlgo.Float(1.5), erlgo.Nil{}}),
+	erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.IntBig{plusTen}}),
+}
+
+func encodeAll(t *testing.T, terms []erlgo.Term) []byte {
+	var buf bytes.Buffer
+	enc := erlgo.NewEncoder(&buf)
+	for _, term := range terms {
+		if err := enc.Encode(term); err != nil {
+			t.Fatalf(`%#v encountered error "%v" while encoding.`, term, err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestDecoderReadsSuccessiveTerms(t *testing.T) {
+	data := encodeAll(t, decoderTerms)
+
+	for name, r := range map[string]io.Reader{
+		"whole":        bytes.NewReader(data),
+		"byte by byte": iotest.OneByteReader(bytes.NewReader(data)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			dec := erlgo.NewDecoder(r)
+			for _, expect := range decoderTerms {
+				if val, err := dec.Decode(); err != nil {
+					t.Fatalf(`encountered error "%v", expected %#v.`, err, expect)
+				} else if !val.Matches(expect) {
+					t.Errorf(`decoded %#v, expected %#v.`, val, expect)
+				}
+			}
+
+			if val, err := dec.Decode(); err != io.EOF {
+				t.Errorf(`decoded (%#v, %v) at the end of the stream, expected io.EOF.`, val, err)
+			}
+		})
+	}
+}
+
+func TestDecoderTruncatedTerm(t *testing.T) {
+	data := encodeAll(t, decoderTerms)
+
+	// cutting off anywhere within the last term must not look like a clean end
+	last := encodeAll(t, decoderTerms[len(decoderTerms)-1:])
+	for cut := 1; cut < len(last); cut++ {
+		dec := erlgo.NewDecoder(bytes.NewReader(data[:len(data)-cut]))
+		for range decoderTerms[:len(decoderTerms)-1] {
+			if _, err := dec.Decode(); err != nil {
+				t.Fatalf(`encountered error "%v" before the truncated term.`, err)
+			}
+		}
+
+		if val, err := dec.Decode(); !errors.Is(err, erlgo.ErrTruncated) {
+			t.Errorf(`cutting %v bytes decoded (%#v, %v), expected ErrTruncated.`, cut, val, err)
+		}
+	}
+}
+
+type timeoutReader struct{}
+
+func (timeoutReader) Read([]byte) (int, error) {
+	return 0, iotest.ErrTimeout
+}
+
+func TestDecoderPassesReadErrors(t *testing.T) {
+	dec := erlgo.NewDecoder(io.MultiReader(bytes.NewReader([]byte{131, 97, 1}), timeoutReader{}))
+
+	if _, err := dec.Decode(); err != nil {
+		t.Fatalf(`encountered error "%v" on the first term.`, err)
+	}
+	if _, err := dec.Decode(); err != iotest.ErrTimeout {
+		t.Errorf(`returned error "%v", expected "%v".`, err, iotest.ErrTimeout)
+	}
+}
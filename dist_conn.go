@@ -0,0 +1,156 @@
+package erlgo
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+const passThrough = 'p'
+
+// DistConn is an established distribution connection to a peer node. It
+// ticks the peer in the background until it is closed.
+type DistConn struct {
+	conn net.Conn
+	r    *PacketReader
+	w    *PacketWriter
+	wmu  sync.Mutex
+	tick time.Duration
+
+	// flags are the ones both nodes announced
+	flags DistFlags
+	dec   *DistDecoder
+
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// Peer is the full name of the peer node.
+	Peer         string
+	PeerFlags    DistFlags
+	PeerCreation uint32
+}
+
+func newDistConn(n *Node, conn net.Conn, peer string, flags DistFlags, creation uint32) *DistConn {
+	tick := n.TickInterval
+	if tick == 0 {
+		tick = defaultTickTime
+	}
+
+	c := &DistConn{
+		conn:         conn,
+		r:            NewPacketReader(conn, 4),
+		w:            NewPacketWriter(conn, 4),
+		tick:         tick,
+		flags:        n.flags() & flags,
+		dec:          NewDistDecoder(),
+		done:         make(chan struct{}),
+		Peer:         peer,
+		PeerFlags:    flags,
+		PeerCreation: creation,
+	}
+	go c.ticker()
+
+	return c
+}
+
+func (c *DistConn) ticker() {
+	t := time.NewTicker(c.tick)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-t.C:
+			if err := c.WriteMessage(nil); err != nil {
+				c.Close()
+				return
+			}
+		}
+	}
+}
+
+// ReadMessage returns the next message sent by the peer, without its length
+// header. Ticks are skipped. It fails if the peer stays silent for four tick
+// intervals.
+func (c *DistConn) ReadMessage() ([]byte, error) {
+	for {
+		c.conn.SetReadDeadline(time.Now().Add(4 * c.tick))
+
+		data, err := c.r.ReadPacket()
+		if err != nil {
+			return nil, err
+		} else if len(data) > 0 {
+			return data, nil
+		}
+	}
+}
+
+// WriteMessage sends data as a single message, an empty message is a tick.
+// It is safe to be called concurrently.
+func (c *DistConn) WriteMessage(data []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	return c.w.WritePacket(data)
+}
+
+// Send sends a control message, optionally followed by a message term, which
+// may be nil. If both nodes support it, the terms follow a distribution header
+// without atom cache references.
+func (c *DistConn) Send(control, message Term) error {
+	withHeader := c.flags&FlagDistHdrAtomCache != 0
+
+	var buf bytes.Buffer
+	if withHeader {
+		buf.Write([]byte{131, distHeader, 0})
+	} else {
+		buf.WriteByte(passThrough)
+	}
+
+	for _, t := range []Term{control, message} {
+		if t == nil {
+			continue
+		}
+
+		if !withHeader {
+			buf.WriteByte(131)
+		}
+		if err := encodeTerm(&buf, t); err != nil {
+			return err
+		}
+	}
+
+	return c.WriteMessage(buf.Bytes())
+}
+
+// Receive reads the next message, returning its control message and the
+// message term, which is nil if the control message has none. Fragmented
+// messages are put together first.
+func (c *DistConn) Receive() (control, message Term, err error) {
+	for control == nil {
+		data, err := c.ReadMessage()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if control, message, err = c.dec.Decode(data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return control, message, nil
+}
+
+// Close stops ticking and closes the connection.
+func (c *DistConn) Close() error {
+	err := fmt.Errorf("connection to %v is already closed", c.Peer)
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.conn.Close()
+	})
+
+	return err
+}
@@ -0,0 +1,176 @@
+package erlgo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+)
+
+const (
+	distHeader         = 68
+	distFragmentHeader = 69
+	distFragment       = 70
+	atomCacheSize      = 2048
+)
+
+// DistDecoder decodes the messages received on a single distribution
+// connection. It keeps the atom cache the peer populates through the
+// distribution headers, and the fragments of messages which are not complete
+// yet.
+type DistDecoder struct {
+	cache     [atomCacheSize]Atom
+	cached    [atomCacheSize]bool
+	fragments map[uint64]*fragmentedMessage
+}
+
+type fragmentedMessage struct {
+	refs []Atom
+	next uint64
+	data []byte
+}
+
+func NewDistDecoder() *DistDecoder {
+	return &DistDecoder{fragments: map[uint64]*fragmentedMessage{}}
+}
+
+// Decode decodes a message, without its length header, into its control
+// message and the message term, which is nil if the control message has none.
+// While a fragmented message is incomplete, control is nil as well.
+func (d *DistDecoder) Decode(data []byte) (control, message Term, err error) {
+	if len(data) > 0 && data[0] == passThrough {
+		return decodeDistTerms(FromBytes(data[1:]), true)
+	} else if len(data) < 2 || data[0] != 131 {
+		return nil, nil, fmt.Errorf("%v is not a valid distribution message", data)
+	}
+
+	b := FromBytes(data[2:])
+
+	switch data[1] {
+	case distHeader:
+		if b.refs, err = d.readHeader(b); err != nil {
+			return nil, nil, err
+		}
+		return decodeDistTerms(b, false)
+	case distFragmentHeader:
+		return d.decodeFragment(b, true)
+	case distFragment:
+		return d.decodeFragment(b, false)
+	default:
+		return nil, nil, fmt.Errorf("%v is not tagging a distribution header", data[1])
+	}
+}
+
+func (d *DistDecoder) decodeFragment(b ErlExtBinary, first bool) (Term, Term, error) {
+	ids, err := readBytes(b, 16)
+	if err != nil {
+		return nil, nil, err
+	}
+	seq, id := binary.BigEndian.Uint64(ids), binary.BigEndian.Uint64(ids[8:])
+
+	f, ok := d.fragments[seq]
+	if first {
+		refs, err := d.readHeader(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		f = &fragmentedMessage{refs: refs, next: id}
+		d.fragments[seq] = f
+	} else if !ok {
+		return nil, nil, fmt.Errorf("fragment %v of sequence %v has no header", id, seq)
+	}
+
+	if id != f.next || id == 0 {
+		delete(d.fragments, seq)
+		return nil, nil, fmt.Errorf("fragment %v of sequence %v is out of order", id, seq)
+	}
+
+	data, err := ioutil.ReadAll(b.bs)
+	if err != nil {
+		return nil, nil, err
+	}
+	f.data = append(f.data, data...)
+	f.next--
+
+	if id > 1 {
+		return nil, nil, nil
+	}
+
+	delete(d.fragments, seq)
+	b = FromBytes(f.data)
+	b.refs = f.refs
+
+	return decodeDistTerms(b, false)
+}
+
+// readHeader updates the atom cache from the distribution header and returns
+// the atoms ATOM_CACHE_REF refers to.
+func (d *DistDecoder) readHeader(b ErlExtBinary) ([]Atom, error) {
+	count, err := b.bs.ReadByte()
+	if err != nil || count == 0 {
+		return nil, err
+	}
+
+	// each reference has a nibble of flags, followed by one for the header
+	flags, err := readBytes(b, int(count)/2+1)
+	if err != nil {
+		return nil, err
+	}
+	nibble := func(i int) byte { return flags[i/2] >> uint(4*(i%2)) & 0xf }
+	longAtoms := nibble(int(count))&1 != 0
+
+	refs := make([]Atom, count)
+	for i := range refs {
+		internal, err := b.bs.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		index := int(nibble(i)&7)<<8 | int(internal)
+
+		if nibble(i)&8 != 0 {
+			var length uint16
+			if longAtoms {
+				length, err = readUint16(b)
+			} else {
+				var short byte
+				short, err = b.bs.ReadByte()
+				length = uint16(short)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			atom, err := readAtomText(b, int(length), true)
+			if err != nil {
+				return nil, err
+			}
+			d.cache[index], d.cached[index] = atom.(Atom), true
+		} else if !d.cached[index] {
+			return nil, fmt.Errorf("atom cache entry %v is not set", index)
+		}
+
+		refs[i] = d.cache[index]
+	}
+
+	return refs, nil
+}
+
+// decodeDistTerms decodes the control message and the optional message term
+// following it. Only pass through messages prefix each term with the version.
+func decodeDistTerms(b ErlExtBinary, versioned bool) (control, message Term, err error) {
+	decode := decodeRemaining
+	if versioned {
+		decode = ErlExtBinary.Decode
+	}
+
+	if control, err = decode(b); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := b.bs.Peek(1); err == nil {
+		if message, err = decode(b); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return control, message, nil
+}
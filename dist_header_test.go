@@ -0,0 +1,94 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var distHeaderTestTable = []struct {
+	Name          string
+	Data          []byte
+	ExpectControl erlgo.Term
+	ExpectMessage erlgo.Term
+}{
+	{"pass through", []byte{'p', 131, 97, 1, 131, 97, 2}, erlgo.Int64(1), erlgo.Int64(2)},
+	{"pass through without message", []byte{'p', 131, 97, 1}, erlgo.Int64(1), nil},
+	{"no cache refs", []byte{131, 68, 0, 97, 1}, erlgo.Int64(1), nil},
+	{"new cache entries", []byte{131, 68, 2, 0x98, 0, 5, 3, 'f', 'o', 'o', 7, 3, 'b', 'a', 'r', 104, 2, 82, 0, 82, 1, 97, 1},
+		erlgo.Tuple{erlgo.Atom("foo"), erlgo.Atom("bar")}, erlgo.Int64(1)},
+	{"cached entries", []byte{131, 68, 2, 0x10, 0, 5, 7, 104, 2, 82, 1, 82, 0},
+		erlgo.Tuple{erlgo.Atom("bar"), erlgo.Atom("foo")}, nil},
+	{"long atoms", []byte{131, 68, 1, 0x1f, 0xff, 0, 3, 'b', 'a', 'z', 82, 0}, erlgo.Atom("baz"), nil},
+	{"utf8 atom", []byte{131, 68, 1, 0x08, 0, 2, 0xc3, 0xa4, 82, 0}, erlgo.Atom("ä"), nil},
+}
+
+// TestDistDecoder runs the table in order, as later entries depend on the
+// atom cache populated by earlier ones.
+func TestDistDecoder(t *testing.T) {
+	d := erlgo.NewDistDecoder()
+	for _, test := range distHeaderTestTable {
+		control, message, err := d.Decode(test.Data)
+		if err != nil {
+			t.Errorf(`%v: encountered error "%v", expected %#v and %#v.`, test.Name, err, test.ExpectControl, test.ExpectMessage)
+		} else if !control.Matches(test.ExpectControl) {
+			t.Errorf(`%v: decoded control %#v, expected %#v.`, test.Name, control, test.ExpectControl)
+		} else if (message == nil) != (test.ExpectMessage == nil) || message != nil && !message.Matches(test.ExpectMessage) {
+			t.Errorf(`%v: decoded message %#v, expected %#v.`, test.Name, message, test.ExpectMessage)
+		}
+	}
+}
+
+func TestDistDecoderFragments(t *testing.T) {
+	d := erlgo.NewDistDecoder()
+
+	fragments := [][]byte{
+		{131, 69, 0, 0, 0, 0, 0, 0, 0, 9, 0, 0, 0, 0, 0, 0, 0, 3, 1, 0x08, 4, 3, 'b', 'a', 'z', 104, 2},
+		{131, 70, 0, 0, 0, 0, 0, 0, 0, 9, 0, 0, 0, 0, 0, 0, 0, 2, 82},
+		{131, 70, 0, 0, 0, 0, 0, 0, 0, 9, 0, 0, 0, 0, 0, 0, 0, 1, 0, 97, 2},
+	}
+	for i, fragment := range fragments[:2] {
+		if control, message, err := d.Decode(fragment); err != nil || control != nil || message != nil {
+			t.Fatalf(`fragment %v returned (%#v, %#v, %v), expected to wait for more.`, i, control, message, err)
+		}
+	}
+
+	expect := erlgo.Tuple{erlgo.Atom("baz"), erlgo.Int64(2)}
+	if control, _, err := d.Decode(fragments[2]); err != nil {
+		t.Errorf(`last fragment encountered error "%v", expected %#v.`, err, expect)
+	} else if !control.Matches(expect) {
+		t.Errorf(`fragments decoded into %#v, expected %#v.`, control, expect)
+	}
+
+	// a single fragment is a complete message
+	single := []byte{131, 69, 0, 0, 0, 0, 0, 0, 0, 10, 0, 0, 0, 0, 0, 0, 0, 1, 1, 0x00, 4, 82, 0}
+	if control, _, err := d.Decode(single); err != nil {
+		t.Errorf(`single fragment encountered error "%v".`, err)
+	} else if !control.Matches(erlgo.Atom("baz")) {
+		t.Errorf(`single fragment decoded into %#v, expected baz.`, control)
+	}
+}
+
+func TestDistDecoderErrors(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Data []byte
+	}{
+		{"empty", []byte{}},
+		{"unknown header", []byte{131, 71, 0}},
+		{"missing version", []byte{68, 0, 97, 1}},
+		{"unset cache entry", []byte{131, 68, 1, 0x02, 0, 82, 0}},
+		{"reference out of range", []byte{131, 68, 0, 82, 0}},
+		{"fragment without header", []byte{131, 70, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 97, 1}},
+		{"truncated header", []byte{131, 68, 2, 0x88}},
+	} {
+		if control, message, err := erlgo.NewDistDecoder().Decode(test.Data); err == nil {
+			t.Errorf(`%v: decoded (%#v, %#v), expected an error.`, test.Name, control, message)
+		}
+	}
+
+	d := erlgo.NewDistDecoder()
+	d.Decode([]byte{131, 69, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 3, 0, 104, 2})
+	if _, _, err := d.Decode([]byte{131, 70, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 97, 1, 97, 2}); err == nil {
+		t.Errorf(`skipping a fragment succeeded, expected an error.`)
+	}
+}
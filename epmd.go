@@ -0,0 +1,222 @@
+package erlgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"time"
+)
+
+const (
+	epmdAlive2XResp  = 118
+	epmdPort2Resp    = 119
+	epmdAlive2Req    = 120
+	epmdAlive2Resp   = 121
+	epmdPortPlease2  = 122
+	epmdDefaultPort  = 4369
+	epmdTimeout      = 5 * time.Second
+	nodeTypeNormal   = 77
+	nodeTypeHidden   = 72
+	nodeProtocolTCP  = 0
+	lowestDistVsn    = 5
+	highestDistVsn   = 6
+	maxNodeNameBytes = 0xffff
+)
+
+// NodeEntry is what EPMD knows about a node.
+type NodeEntry struct {
+	// Name is the part of the node name before the @.
+	Name           string
+	Port           uint16
+	Hidden         bool
+	Protocol       uint8
+	HighestVersion uint16
+	LowestVersion  uint16
+	Extra          []byte
+}
+
+// EPMD talks to the Erlang Port Mapper Daemon listening on Addr, which
+// defaults to localhost:4369. Timeout limits connecting and waiting for each
+// response, it defaults to 5 seconds.
+type EPMD struct {
+	Addr    string
+	Timeout time.Duration
+}
+
+func (e *EPMD) timeout() time.Duration {
+	if e.Timeout > 0 {
+		return e.Timeout
+	}
+	return epmdTimeout
+}
+
+// dial connects to EPMD, with a deadline for the request to be answered.
+func (e *EPMD) dial() (net.Conn, error) {
+	addr := e.Addr
+	if addr == "" {
+		addr = fmt.Sprintf("localhost:%v", epmdDefaultPort)
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, e.timeout())
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(e.timeout()))
+
+	return conn, nil
+}
+
+// PortPlease looks up the port the node with the given short name is
+// listening on, using PORT_PLEASE2_REQ.
+func (e *EPMD) PortPlease(name string) (NodeEntry, error) {
+	conn, err := e.dial()
+	if err != nil {
+		return NodeEntry{}, err
+	}
+	defer conn.Close()
+
+	if err := NewPacketWriter(conn, 2).WritePacket(append([]byte{epmdPortPlease2}, name...)); err != nil {
+		return NodeEntry{}, err
+	}
+
+	// the response is not framed, EPMD closes the connection after it
+	resp, err := ioutil.ReadAll(conn)
+	if err != nil {
+		return NodeEntry{}, err
+	} else if len(resp) < 2 || resp[0] != epmdPort2Resp {
+		return NodeEntry{}, fmt.Errorf("%v is not a valid PORT2_RESP", resp)
+	} else if resp[1] != 0 {
+		return NodeEntry{}, fmt.Errorf("EPMD does not know node %q (result %v)", name, resp[1])
+	}
+
+	return parseNodeEntry(resp[2:])
+}
+
+func parseNodeEntry(data []byte) (NodeEntry, error) {
+	r := bytes.NewReader(data)
+
+	var fixed struct {
+		Port           uint16
+		NodeType       uint8
+		Protocol       uint8
+		HighestVersion uint16
+		LowestVersion  uint16
+		NameLength     uint16
+	}
+	if err := binary.Read(r, binary.BigEndian, &fixed); err != nil {
+		return NodeEntry{}, err
+	}
+
+	name := make([]byte, fixed.NameLength)
+	if _, err := io.ReadFull(r, name); err != nil {
+		return NodeEntry{}, err
+	}
+
+	var extraLength uint16
+	if err := binary.Read(r, binary.BigEndian, &extraLength); err != nil {
+		return NodeEntry{}, err
+	}
+
+	extra := make([]byte, extraLength)
+	if _, err := io.ReadFull(r, extra); err != nil {
+		return NodeEntry{}, err
+	}
+
+	return NodeEntry{
+		Name:           string(name),
+		Port:           fixed.Port,
+		Hidden:         fixed.NodeType == nodeTypeHidden,
+		Protocol:       fixed.Protocol,
+		HighestVersion: fixed.HighestVersion,
+		LowestVersion:  fixed.LowestVersion,
+		Extra:          extra,
+	}, nil
+}
+
+// Registration keeps a node registered with EPMD, until it is closed.
+type Registration struct {
+	conn net.Conn
+
+	// Creation is the value EPMD assigned to this incarnation of the node.
+	Creation uint32
+}
+
+func (r *Registration) Close() error {
+	return r.conn.Close()
+}
+
+// Register announces a node with ALIVE2_REQ. Name, Port and Hidden are taken
+// from entry, the protocol and versions are filled in as supported by this
+// package.
+func (e *EPMD) Register(entry NodeEntry) (*Registration, error) {
+	if len(entry.Name) > maxNodeNameBytes || len(entry.Extra) > maxNodeNameBytes {
+		return nil, fmt.Errorf("node name %q or its extra data is too long", entry.Name)
+	}
+
+	conn, err := e.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeType := byte(nodeTypeNormal)
+	if entry.Hidden {
+		nodeType = nodeTypeHidden
+	}
+
+	var req bytes.Buffer
+	req.WriteByte(epmdAlive2Req)
+	writeUint16(&req, entry.Port)
+	req.WriteByte(nodeType)
+	req.WriteByte(nodeProtocolTCP)
+	writeUint16(&req, highestDistVsn)
+	writeUint16(&req, lowestDistVsn)
+	writeUint16(&req, uint16(len(entry.Name)))
+	req.WriteString(entry.Name)
+	writeUint16(&req, uint16(len(entry.Extra)))
+	req.Write(entry.Extra)
+
+	if err := NewPacketWriter(conn, 2).WritePacket(req.Bytes()); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	creation, err := readAlive2Response(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	return &Registration{conn: conn, Creation: creation}, nil
+}
+
+func readAlive2Response(conn net.Conn) (uint32, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, err
+	}
+
+	if header[1] != 0 {
+		return 0, fmt.Errorf("EPMD refused the registration (result %v)", header[1])
+	}
+
+	switch header[0] {
+	case epmdAlive2Resp:
+		creation := make([]byte, 2)
+		if _, err := io.ReadFull(conn, creation); err != nil {
+			return 0, err
+		}
+		return uint32(binary.BigEndian.Uint16(creation)), nil
+	case epmdAlive2XResp:
+		creation := make([]byte, 4)
+		if _, err := io.ReadFull(conn, creation); err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint32(creation), nil
+	default:
+		return 0, fmt.Errorf("%v is not a valid ALIVE2 response", header[0])
+	}
+}
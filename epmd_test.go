@@ -0,0 +1,156 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/NobbZ/erlgo"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeEPMD implements the ALIVE2 and PORT_PLEASE2 requests of EPMD.
+type fakeEPMD struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	nodes    map[string][]byte
+	creation uint32
+}
+
+func newFakeEPMD(t *testing.T) *fakeEPMD {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(`listening for the fake EPMD failed with "%v".`, err)
+	}
+
+	e := &fakeEPMD{ln: ln, nodes: map[string][]byte{}, creation: 1000}
+	go e.serve()
+
+	return e
+}
+
+func (e *fakeEPMD) Addr() string {
+	return e.ln.Addr().String()
+}
+
+func (e *fakeEPMD) Close() {
+	e.ln.Close()
+}
+
+func (e *fakeEPMD) serve() {
+	for {
+		conn, err := e.ln.Accept()
+		if err != nil {
+			return
+		}
+		go e.handle(conn)
+	}
+}
+
+func (e *fakeEPMD) handle(conn net.Conn) {
+	defer conn.Close()
+
+	req, err := erlgo.NewPacketReader(conn, 2).ReadPacket()
+	if err != nil || len(req) == 0 {
+		return
+	}
+
+	switch req[0] {
+	case 120:
+		// the entry of PORT2_RESP is the request without its tag
+		entry := req[1:]
+		name := string(entry[10 : 10+binary.BigEndian.Uint16(entry[8:])])
+
+		e.mu.Lock()
+		e.creation++
+		creation := e.creation
+		e.nodes[name] = entry
+		e.mu.Unlock()
+
+		resp := []byte{118, 0, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(resp[2:], creation)
+		conn.Write(resp)
+
+		// the registration lasts as long as the connection
+		conn.Read(make([]byte, 1))
+
+		e.mu.Lock()
+		delete(e.nodes, name)
+		e.mu.Unlock()
+	case 122:
+		e.mu.Lock()
+		entry, ok := e.nodes[string(req[1:])]
+		e.mu.Unlock()
+
+		if ok {
+			conn.Write(append([]byte{119, 0}, entry...))
+		} else {
+			conn.Write([]byte{119, 1})
+		}
+	}
+}
+
+func TestEPMDRegistration(t *testing.T) {
+	fake := newFakeEPMD(t)
+	defer fake.Close()
+
+	epmd := erlgo.EPMD{Addr: fake.Addr()}
+
+	reg, err := epmd.Register(erlgo.NodeEntry{Name: "gonode", Port: 4711, Hidden: true, Extra: []byte{1, 2}})
+	if err != nil {
+		t.Fatalf(`registration failed with "%v".`, err)
+	} else if reg.Creation != 1001 {
+		t.Errorf(`registration got creation %v, expected 1001.`, reg.Creation)
+	}
+
+	expect := erlgo.NodeEntry{
+		Name:           "gonode",
+		Port:           4711,
+		Hidden:         true,
+		HighestVersion: 6,
+		LowestVersion:  5,
+		Extra:          []byte{1, 2},
+	}
+	if entry, err := epmd.PortPlease("gonode"); err != nil {
+		t.Errorf(`looking up gonode failed with "%v".`, err)
+	} else if entry.Name != expect.Name || entry.Port != expect.Port || entry.Hidden != expect.Hidden ||
+		entry.HighestVersion != expect.HighestVersion || entry.LowestVersion != expect.LowestVersion ||
+		!bytes.Equal(entry.Extra, expect.Extra) {
+		t.Errorf(`looking up gonode returned %#v, expected %#v.`, entry, expect)
+	}
+
+	if entry, err := epmd.PortPlease("unknown"); err == nil {
+		t.Errorf(`looking up unknown returned %#v, expected an error.`, entry)
+	}
+
+	reg.Close()
+}
+
+func TestEPMDTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(`listening for the silent EPMD failed with "%v".`, err)
+	}
+	defer ln.Close()
+
+	// accepts connections, but never answers
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	epmd := erlgo.EPMD{Addr: ln.Addr().String(), Timeout: 50 * time.Millisecond}
+	if entry, err := epmd.PortPlease("gonode"); err == nil {
+		t.Errorf(`looking up gonode returned %#v, expected a timeout.`, entry)
+	}
+	if reg, err := epmd.Register(erlgo.NodeEntry{Name: "gonode", Port: 4711}); err == nil {
+		reg.Close()
+		t.Errorf(`registration succeeded, expected a timeout.`)
+	}
+}
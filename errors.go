@@ -0,0 +1,132 @@
+package erlgo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedTag is the cause of a SyntaxError for an unknown tag.
+	ErrUnsupportedTag = errors.New("unsupported tag")
+
+	// ErrBadVersion is the cause of a SyntaxError for a term not starting
+	// with version 131.
+	ErrBadVersion = errors.New("bad version")
+
+	// ErrDuplicateKey is the cause of a SyntaxError for a map key which
+	// matches an earlier key of the same map.
+	ErrDuplicateKey = errors.New("duplicate map key")
+
+	// ErrTruncated is the cause of a SyntaxError for input ending within a
+	// term. It is io.ErrUnexpectedEOF, so both can be checked for.
+	ErrTruncated = io.ErrUnexpectedEOF
+)
+
+// SyntaxError describes where decoding a term failed. Err is the cause, which
+// may also be a LimitError, an UnknownAtomError, a CompressedSizeError or an
+// error of the underlying reader.
+type SyntaxError struct {
+	// Offset is the position of the tag of the failing term in the input,
+	// or in the inflated data of a compressed term.
+	Offset int64
+	Tag    uint8
+
+	// Path leads to the failing term, like tuple[1].map[key].list[5],
+	// indexes start at 0.
+	Path string
+	Err  error
+}
+
+func (e *SyntaxError) Error() string {
+	msg := fmt.Sprintf("erlgo: decoding tag %v at offset %v", e.Tag, e.Offset)
+	if e.Path != "" {
+		msg += " in " + e.Path
+	}
+
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// countingReader counts the bytes read by the bufio.Reader on top of it, to
+// calculate the offsets in the input.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (b ErlExtBinary) offset() int64 {
+	if b.count == nil {
+		return 0
+	}
+
+	return b.count.n - int64(b.bs.Buffered())
+}
+
+// syntaxError wraps err with the position of the term, unless it already
+// describes a nested term.
+func (b ErlExtBinary) syntaxError(offset int64, tag uint8, err error) error {
+	if _, ok := err.(*SyntaxError); ok {
+		return err
+	}
+
+	if err == io.EOF {
+		err = ErrTruncated
+	}
+
+	return &SyntaxError{Offset: offset, Tag: tag, Path: b.state.pathString(), Err: err}
+}
+
+// pathElem is a step into a compound term. It is formatted only if an error
+// needs it, as formatting map keys is expensive.
+type pathElem struct {
+	kind  string
+	index int
+	key   Term
+}
+
+func (p pathElem) String() string {
+	switch {
+	case p.key != nil:
+		return fmt.Sprintf("%v[%v]", p.kind, p.key)
+	case p.index >= 0:
+		return fmt.Sprintf("%v[%v]", p.kind, p.index)
+	default:
+		return p.kind
+	}
+}
+
+func (s *decodeState) push(kind string, index int, key Term) {
+	if s != nil {
+		s.path = append(s.path, pathElem{kind, index, key})
+	}
+}
+
+func (s *decodeState) pop() {
+	if s != nil {
+		s.path = s.path[:len(s.path)-1]
+	}
+}
+
+func (s *decodeState) pathString() string {
+	if s == nil {
+		return ""
+	}
+
+	elems := make([]string, len(s.path))
+	for i, p := range s.path {
+		elems[i] = p.String()
+	}
+
+	return strings.Join(elems, ".")
+}
@@ -0,0 +1,90 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var syntaxErrorTestTable = []struct {
+	Name   string
+	Data   []byte
+	Expect erlgo.SyntaxError
+}{
+	{"empty", []byte{}, erlgo.SyntaxError{Offset: 0, Tag: 0, Path: "", Err: erlgo.ErrTruncated}},
+	{"bad version", []byte{130, 97, 1}, erlgo.SyntaxError{Offset: 0, Tag: 130, Path: "", Err: erlgo.ErrBadVersion}},
+	{"unsupported tag", []byte{131, 104, 2, 97, 1, 200}, erlgo.SyntaxError{Offset: 5, Tag: 200, Path: "tuple[1]", Err: erlgo.ErrUnsupportedTag}},
+	{"truncated list element", []byte{131, 108, 0, 0, 0, 2, 97, 1, 98, 0, 0}, erlgo.SyntaxError{Offset: 8, Tag: 98, Path: "list[1]", Err: erlgo.ErrTruncated}},
+	{"missing list tail", []byte{131, 108, 0, 0, 0, 1, 97, 1}, erlgo.SyntaxError{Offset: 8, Tag: 0, Path: "list.tail", Err: erlgo.ErrTruncated}},
+	{"map value", []byte{131, 116, 0, 0, 0, 1, 119, 1, 'k', 104, 1, 108, 0, 0, 0, 1, 200},
+		erlgo.SyntaxError{Offset: 16, Tag: 200, Path: "map[k].tuple[0].list[0]", Err: erlgo.ErrUnsupportedTag}},
+	{"map key", []byte{131, 116, 0, 0, 0, 2, 97, 1, 97, 2, 200}, erlgo.SyntaxError{Offset: 10, Tag: 200, Path: "map.key[1]", Err: erlgo.ErrUnsupportedTag}},
+	{"duplicate map key", []byte{131, 116, 0, 0, 0, 2, 97, 1, 97, 2, 97, 1, 97, 3},
+		erlgo.SyntaxError{Offset: 10, Tag: 97, Path: "map.key[1]", Err: erlgo.ErrDuplicateKey}},
+	{"duplicate big map key", []byte{131, 116, 0, 0, 0, 2, 97, 1, 97, 2, 110, 1, 0, 1, 97, 3},
+		erlgo.SyntaxError{Offset: 10, Tag: 110, Path: "map.key[1]", Err: erlgo.ErrDuplicateKey}},
+	{"compressed", compressedData(5, []byte{120, 156, 75, 100, 4, 0, 0, 197, 0, 99}),
+		erlgo.SyntaxError{Offset: 1, Tag: 80, Path: "", Err: nil}},
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	for _, test := range syntaxErrorTestTable {
+		val, err := erlgo.FromBytes(test.Data).Decode()
+
+		var syntaxErr *erlgo.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf(`%v parsed into (%#v, %v), expected a *SyntaxError.`, test.Name, val, err)
+		} else if syntaxErr.Offset != test.Expect.Offset || syntaxErr.Tag != test.Expect.Tag || syntaxErr.Path != test.Expect.Path {
+			t.Errorf(`%v failed at offset %v, tag %v and path %q, expected %v, %v and %q.`, test.Name,
+				syntaxErr.Offset, syntaxErr.Tag, syntaxErr.Path, test.Expect.Offset, test.Expect.Tag, test.Expect.Path)
+		} else if test.Expect.Err != nil && !errors.Is(err, test.Expect.Err) {
+			t.Errorf(`%v failed with "%v", expected "%v".`, test.Name, err, test.Expect.Err)
+		}
+	}
+}
+
+// hugeLengthTestTable announces lengths the input does not hold, which must
+// not be allocated up front even when no limits are set.
+var hugeLengthTestTable = []struct {
+	Name string
+	Data []byte
+}{
+	{"large tuple", []byte{131, 105, 0x7f, 255, 255, 255}},
+	{"list", []byte{131, 108, 0x7f, 255, 255, 255}},
+	{"map", []byte{131, 116, 0x7f, 255, 255, 255}},
+	{"binary", []byte{131, 109, 0xff, 255, 255, 255, 1}},
+	{"bitstring", []byte{131, 77, 0xff, 255, 255, 255, 3, 1}},
+	{"fun free variables", append(append([]byte{131, 117, 0x7f, 255, 255, 255}, funPid...), 119, 1, 109, 97, 3, 98, 0, 0, 1, 0)},
+}
+
+func TestReadingHugeLengths(t *testing.T) {
+	for _, test := range hugeLengthTestTable {
+		if val, err := erlgo.FromBytes(test.Data).Decode(); !errors.Is(err, erlgo.ErrTruncated) {
+			t.Errorf(`%v parsed into (%#v, %v), expected it to be truncated.`, test.Name, val, err)
+		}
+	}
+}
+
+func TestSyntaxErrorMessage(t *testing.T) {
+	_, err := erlgo.FromBytes([]byte{131, 104, 2, 97, 1, 200}).Decode()
+
+	expect := "erlgo: decoding tag 200 at offset 5 in tuple[1]: unsupported tag"
+	if err == nil || err.Error() != expect {
+		t.Errorf(`failed with "%v", expected "%v".`, err, expect)
+	}
+}
+
+func TestSyntaxErrorOffsetInStream(t *testing.T) {
+	d := erlgo.NewDecoder(bytes.NewReader([]byte{131, 97, 1, 131, 104, 1, 200}))
+	if _, err := d.Decode(); err != nil {
+		t.Fatalf(`encountered error "%v" on the first term.`, err)
+	}
+
+	var syntaxErr *erlgo.SyntaxError
+	if _, err := d.Decode(); !errors.As(err, &syntaxErr) {
+		t.Errorf(`failed with "%v", expected a *SyntaxError.`, err)
+	} else if syntaxErr.Offset != 6 || syntaxErr.Path != "tuple[0]" {
+		t.Errorf(`failed at offset %v in %q, expected offset 6 in "tuple[0]".`, syntaxErr.Offset, syntaxErr.Path)
+	}
+}
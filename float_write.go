@@ -0,0 +1,17 @@
+package erlgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+func encodeFloat(buf *bytes.Buffer, f Float) error {
+	var bits [8]byte
+	binary.BigEndian.PutUint64(bits[:], math.Float64bits(float64(f)))
+
+	buf.WriteByte(newFloatExt)
+	buf.Write(bits[:])
+
+	return nil
+}
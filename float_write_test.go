@@ -0,0 +1,39 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var floatWriteTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect []byte
+}{
+	{"0.0", erlgo.Float(0.0), []byte{131, 70, 0, 0, 0, 0, 0, 0, 0, 0}},
+	{"1.5", erlgo.Float(1.5), []byte{131, 70, 63, 248, 0, 0, 0, 0, 0, 0}},
+	{"-0.1", erlgo.Float(-0.1), []byte{131, 70, 191, 185, 153, 153, 153, 153, 153, 154}},
+}
+
+func TestWritingFloats(t *testing.T) {
+	for _, test := range floatWriteTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil && !bytes.Equal(data, test.Expect) {
+				t.Errorf(`%#v encoded into %v, expected %v.`, test.Term, data, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %v.`, test.Term, err, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkWritingFloats(b *testing.B) {
+	for _, data := range floatWriteTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Encode(data.Term)
+			}
+		})
+	}
+}
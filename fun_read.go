@@ -0,0 +1,264 @@
+package erlgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Fun is a local fun created by the code in Module. Funs decoded from the
+// legacy FUN_EXT have Legacy set, for those only OldIndex and OldUniq are
+// known, while Arity, Uniq and Index stay zero.
+type Fun struct {
+	Module   Atom
+	Arity    uint8
+	Uniq     [16]byte
+	Index    uint32
+	OldIndex uint32
+	OldUniq  uint32
+	Pid      Pid
+	Free     []Term
+	Legacy   bool
+}
+
+func (f Fun) IsInteger() bool {
+	return false
+}
+
+func (f Fun) IsList() bool { return false }
+
+func (f Fun) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+func (f Fun) Matches(other Term) bool {
+	switch o := other.(type) {
+	case Fun:
+		if f.Module != o.Module || f.Arity != o.Arity || !bytes.Equal(f.Uniq[:], o.Uniq[:]) ||
+			f.Index != o.Index || f.OldIndex != o.OldIndex || f.OldUniq != o.OldUniq ||
+			f.Pid != o.Pid || f.Legacy != o.Legacy || len(f.Free) != len(o.Free) {
+			return false
+		}
+		for i := range f.Free {
+			if !f.Free[i].Matches(o.Free[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// Export is an external fun, as created by `fun Module:Function/Arity`.
+type Export struct {
+	Module   Atom
+	Function Atom
+	Arity    uint8
+}
+
+func (e Export) IsInteger() bool {
+	return false
+}
+
+func (e Export) IsList() bool { return false }
+
+func (e Export) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+func (e Export) Matches(other Term) bool {
+	switch o := other.(type) {
+	case Export:
+		return e == o
+	default:
+		return false
+	}
+}
+
+func decodeNewFun(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != newFunExt {
+		return nil, fmt.Errorf("%v is not tagging a new fun", tag)
+	}
+
+	if err := b.state.checkFun(); err != nil {
+		return nil, err
+	}
+
+	// The total size is of no use to us, as we decode the fun completely.
+	if _, err := readInt32(b); err != nil {
+		return nil, err
+	}
+
+	arity, err := b.bs.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	result := Fun{Arity: arity}
+
+	uniq, err := readBytes(b, len(result.Uniq))
+	if err != nil {
+		return nil, err
+	}
+	copy(result.Uniq[:], uniq)
+
+	index, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	}
+	result.Index = uint32(index)
+
+	numFree, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Module, err = readFunAtom(b); err != nil {
+		return nil, err
+	}
+	if result.OldIndex, err = readFunInteger(b); err != nil {
+		return nil, err
+	}
+	if result.OldUniq, err = readFunInteger(b); err != nil {
+		return nil, err
+	}
+	if result.Pid, err = readFunPid(b); err != nil {
+		return nil, err
+	}
+	if result.Free, err = readFreeVars(b, uint32(numFree)); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func decodeFun(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != funExt {
+		return nil, fmt.Errorf("%v is not tagging a fun", tag)
+	}
+
+	if err := b.state.checkFun(); err != nil {
+		return nil, err
+	}
+
+	numFree, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	}
+
+	result := Fun{Legacy: true}
+
+	if result.Pid, err = readFunPid(b); err != nil {
+		return nil, err
+	}
+	if result.Module, err = readFunAtom(b); err != nil {
+		return nil, err
+	}
+	if result.OldIndex, err = readFunInteger(b); err != nil {
+		return nil, err
+	}
+	if result.OldUniq, err = readFunInteger(b); err != nil {
+		return nil, err
+	}
+	if result.Free, err = readFreeVars(b, uint32(numFree)); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func decodeExport(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != exportExt {
+		return nil, fmt.Errorf("%v is not tagging an export", tag)
+	}
+
+	if err := b.state.checkFun(); err != nil {
+		return nil, err
+	}
+
+	module, err := readFunAtom(b)
+	if err != nil {
+		return nil, err
+	}
+
+	function, err := readFunAtom(b)
+	if err != nil {
+		return nil, err
+	}
+
+	arity, err := readFunInteger(b)
+	if err != nil {
+		return nil, err
+	} else if arity > math.MaxUint8 {
+		return nil, fmt.Errorf("%v is not a valid arity", arity)
+	}
+
+	return Export{Module: module, Function: function, Arity: uint8(arity)}, nil
+}
+
+func readFunAtom(b ErlExtBinary) (Atom, error) {
+	term, err := decodeRemaining(b)
+	if err != nil {
+		return "", err
+	}
+
+	if atom, ok := term.(Atom); ok {
+		return atom, nil
+	}
+	return "", fmt.Errorf("%#v is not an atom", term)
+}
+
+func readFunInteger(b ErlExtBinary) (uint32, error) {
+	term, err := decodeRemaining(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if i, err := term.ToInteger(); err != nil {
+		return 0, err
+	} else if v, ok := i.Int64(); !ok || v < 0 || v > math.MaxUint32 {
+		return 0, fmt.Errorf("%#v is out of range", term)
+	} else {
+		return uint32(v), nil
+	}
+}
+
+func readFunPid(b ErlExtBinary) (Pid, error) {
+	term, err := decodeRemaining(b)
+	if err != nil {
+		return Pid{}, err
+	}
+
+	if pid, ok := term.(Pid); ok {
+		return pid, nil
+	}
+	return Pid{}, fmt.Errorf("%#v is not a pid", term)
+}
+
+func readFreeVars(b ErlExtBinary, numFree uint32) ([]Term, error) {
+	if err := b.state.checkElements(uint64(numFree)); err != nil {
+		return nil, err
+	}
+
+	result := make([]Term, 0, initialCapacity(numFree))
+
+	for i := uint32(0); i < numFree; i++ {
+		b.state.push("fun.free", int(i), nil)
+		if elem, err := decodeRemaining(b); err != nil {
+			return nil, err
+		} else {
+			result = append(result, elem)
+		}
+		b.state.pop()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+// <0.5.0> on a@b as NEW_PID_EXT
+var funPid = []byte{88, 119, 3, 97, 64, 98, 0, 0, 0, 5, 0, 0, 0, 0, 0, 0, 0, 1}
+var funPidExpect = erlgo.Pid{Node: "a@b", ID: 5, Creation: 1}
+
+func newFunData() []byte {
+	body := []byte{1, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 0, 0, 0, 3, 0, 0, 0, 1, 119, 1, 109, 97, 3, 98, 0, 0, 1, 0}
+	body = append(body, funPid...)
+	body = append(body, 97, 42)
+
+	size := len(body) + 4
+	data := []byte{131, 112, byte(size >> 24), byte(size >> 16), byte(size >> 8), byte(size)}
+	return append(data, body...)
+}
+
+func legacyFunData() []byte {
+	data := append([]byte{131, 117, 0, 0, 0, 1}, funPid...)
+	return append(data, 119, 1, 109, 97, 3, 98, 0, 0, 1, 0, 97, 42)
+}
+
+var newFunExpect = erlgo.Fun{
+	Module:   "m",
+	Arity:    1,
+	Uniq:     [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+	Index:    3,
+	OldIndex: 3,
+	OldUniq:  256,
+	Pid:      funPidExpect,
+	Free:     []erlgo.Term{erlgo.Int64(42)},
+}
+
+var legacyFunExpect = erlgo.Fun{
+	Module:   "m",
+	OldIndex: 3,
+	OldUniq:  256,
+	Pid:      funPidExpect,
+	Free:     []erlgo.Term{erlgo.Int64(42)},
+	Legacy:   true,
+}
+
+var funTestTable = []struct {
+	Name   string
+	Data   erlgo.ErlExtBinary
+	Expect erlgo.Term
+}{
+	{"fun m:'-f/0-fun-0-'/1", erlgo.FromBytes(newFunData()), newFunExpect},
+	{"fun m:'-f/0-fun-0-'/1 (legacy)", erlgo.FromBytes(legacyFunData()), legacyFunExpect},
+	{"fun lists:map/2", erlgo.FromBytes([]byte{131, 113, 119, 5, 108, 105, 115, 116, 115, 119, 3, 109, 97, 112, 97, 2}), erlgo.Export{Module: "lists", Function: "map", Arity: 2}},
+}
+
+func TestReadingFuns(t *testing.T) {
+	for _, test := range funTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if val, err := test.Data.Decode(); err == nil && !val.Matches(test.Expect) {
+				t.Errorf(`%#v parsed into %#v, expected %#v."`, test.Data, val, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected value %#v.`, test.Data, err, test.Expect)
+			}
+		})
+	}
+}
+
+func TestReadingInvalidFuns(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Data erlgo.ErlExtBinary
+	}{
+		{"export with integer module", erlgo.FromBytes([]byte{131, 113, 97, 1, 119, 3, 109, 97, 112, 97, 2})},
+		{"export with atom arity", erlgo.FromBytes([]byte{131, 113, 119, 1, 109, 119, 1, 102, 119, 1, 97})},
+		{"export with too large arity", erlgo.FromBytes([]byte{131, 113, 119, 1, 109, 119, 1, 102, 98, 0, 0, 1, 0})},
+		{"legacy fun without pid", erlgo.FromBytes([]byte{131, 117, 0, 0, 0, 0, 119, 1, 109, 119, 1, 109, 97, 3, 97, 3})},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if val, err := test.Data.Decode(); err == nil {
+				t.Errorf(`%#v parsed into %#v, expected an error.`, test.Data, val)
+			}
+		})
+	}
+}
+
+func BenchmarkReadingFuns(b *testing.B) {
+	for _, data := range funTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				data.Data.Decode()
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package erlgo
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// encodeFun writes NEW_FUN_EXT, unless the fun has been decoded from the
+// legacy FUN_EXT, which lacks the information needed for the newer tag.
+func encodeFun(buf *bytes.Buffer, f Fun) error {
+	if f.Legacy {
+		return encodeLegacyFun(buf, f)
+	}
+
+	start := buf.Len()
+
+	buf.WriteByte(newFunExt)
+	writeUint32(buf, 0) // size, filled in below
+	buf.WriteByte(f.Arity)
+	buf.Write(f.Uniq[:])
+	writeUint32(buf, f.Index)
+	writeUint32(buf, uint32(len(f.Free)))
+	if err := encodeAtom(buf, f.Module); err != nil {
+		return err
+	}
+	if err := encodeInt64(buf, Int64(f.OldIndex)); err != nil {
+		return err
+	}
+	if err := encodeInt64(buf, Int64(f.OldUniq)); err != nil {
+		return err
+	}
+	if err := encodePid(buf, f.Pid); err != nil {
+		return err
+	}
+	for _, free := range f.Free {
+		if err := encodeTerm(buf, free); err != nil {
+			return err
+		}
+	}
+
+	// the size includes itself, but not the tag
+	size := buf.Len() - start - 1
+	binary.BigEndian.PutUint32(buf.Bytes()[start+1:], uint32(size))
+
+	return nil
+}
+
+func encodeLegacyFun(buf *bytes.Buffer, f Fun) error {
+	buf.WriteByte(funExt)
+	writeUint32(buf, uint32(len(f.Free)))
+	if err := encodePid(buf, f.Pid); err != nil {
+		return err
+	}
+	if err := encodeAtom(buf, f.Module); err != nil {
+		return err
+	}
+	if err := encodeInt64(buf, Int64(f.OldIndex)); err != nil {
+		return err
+	}
+	if err := encodeInt64(buf, Int64(f.OldUniq)); err != nil {
+		return err
+	}
+	for _, free := range f.Free {
+		if err := encodeTerm(buf, free); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeExport(buf *bytes.Buffer, e Export) error {
+	buf.WriteByte(exportExt)
+	if err := encodeAtom(buf, e.Module); err != nil {
+		return err
+	}
+	if err := encodeAtom(buf, e.Function); err != nil {
+		return err
+	}
+	return encodeInt64(buf, Int64(e.Arity))
+}
@@ -0,0 +1,39 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var funWriteTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect []byte
+}{
+	{"fun m:'-f/0-fun-0-'/1", newFunExpect, newFunData()},
+	{"fun m:'-f/0-fun-0-'/1 (legacy)", legacyFunExpect, legacyFunData()},
+	{"fun lists:map/2", erlgo.Export{Module: "lists", Function: "map", Arity: 2}, []byte{131, 113, 119, 5, 108, 105, 115, 116, 115, 119, 3, 109, 97, 112, 97, 2}},
+}
+
+func TestWritingFuns(t *testing.T) {
+	for _, test := range funWriteTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil && !bytes.Equal(data, test.Expect) {
+				t.Errorf(`%#v encoded into %v, expected %v.`, test.Term, data, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %v.`, test.Term, err, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkWritingFuns(b *testing.B) {
+	for _, data := range funWriteTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Encode(data.Term)
+			}
+		})
+	}
+}
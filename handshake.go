@@ -0,0 +1,391 @@
+package erlgo
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DistFlags are the capabilities two nodes announce during the handshake.
+type DistFlags uint64
+
+const (
+	FlagPublished          DistFlags = 0x1
+	FlagAtomCache          DistFlags = 0x2
+	FlagExtendedReferences DistFlags = 0x4
+	FlagDistMonitor        DistFlags = 0x8
+	FlagFunTags            DistFlags = 0x10
+	FlagDistMonitorName    DistFlags = 0x20
+	FlagHiddenAtomCache    DistFlags = 0x40
+	FlagNewFunTags         DistFlags = 0x80
+	FlagExtendedPidsPorts  DistFlags = 0x100
+	FlagExportPtrTag       DistFlags = 0x200
+	FlagBitBinaries        DistFlags = 0x400
+	FlagNewFloats          DistFlags = 0x800
+	FlagUnicodeIO          DistFlags = 0x1000
+	FlagDistHdrAtomCache   DistFlags = 0x2000
+	FlagSmallAtomTags      DistFlags = 0x4000
+	FlagUTF8Atoms          DistFlags = 0x10000
+	FlagMapTag             DistFlags = 0x20000
+	FlagBigCreation        DistFlags = 0x40000
+	FlagSendSender         DistFlags = 0x80000
+	FlagBigSeqtraceLabels  DistFlags = 0x100000
+	FlagExitPayload        DistFlags = 0x400000
+	FlagFragments          DistFlags = 0x800000
+	FlagHandshake23        DistFlags = 0x1000000
+	FlagUnlinkID           DistFlags = 0x2000000
+	FlagSpawn              DistFlags = 1 << 32
+	FlagNameMe             DistFlags = 1 << 33
+	FlagV4NC               DistFlags = 1 << 34
+	FlagAlias              DistFlags = 1 << 35
+)
+
+// DefaultFlags are announced by a Node without Flags. They describe the
+// formats this package reads and writes, and do not include FlagPublished,
+// so the node stays hidden.
+const DefaultFlags = FlagExtendedReferences | FlagFunTags | FlagNewFunTags | FlagExtendedPidsPorts |
+	FlagExportPtrTag | FlagBitBinaries | FlagNewFloats | FlagUnicodeIO | FlagDistHdrAtomCache |
+	FlagSmallAtomTags | FlagUTF8Atoms | FlagMapTag | FlagBigCreation | FlagFragments | FlagHandshake23 |
+	FlagUnlinkID | FlagV4NC
+
+// requiredFlags must be supported by the peer, as the Encoder relies on them.
+const requiredFlags = FlagExtendedReferences | FlagExtendedPidsPorts | FlagNewFunTags | FlagExportPtrTag |
+	FlagBitBinaries | FlagNewFloats | FlagUTF8Atoms | FlagMapTag | FlagBigCreation
+
+const (
+	handshakeTimeout   = 7 * time.Second
+	defaultTickTime    = 15 * time.Second
+	handshakeName5     = 'n'
+	handshakeName6     = 'N'
+	handshakeStatus    = 's'
+	handshakeComplete  = 'c'
+	handshakeReply     = 'r'
+	handshakeAck       = 'a'
+	handshakeDigestLen = md5.Size
+)
+
+// Node is the identity of a Go program in an Erlang cluster.
+type Node struct {
+	// Name is the full node name, like gonode@localhost.
+	Name     string
+	Cookie   string
+	Creation uint32
+
+	// Flags default to DefaultFlags.
+	Flags DistFlags
+
+	// TickInterval is the time between ticks sent to an otherwise silent
+	// peer, which defaults to 15 seconds, a quarter of the net_ticktime of
+	// Erlang. A peer which is silent for four intervals is considered down.
+	TickInterval time.Duration
+
+	// EPMD is used to look up peers, the default is the EPMD on the host of
+	// the peer.
+	EPMD *EPMD
+}
+
+func (n *Node) flags() DistFlags {
+	if n.Flags == 0 {
+		return DefaultFlags
+	}
+
+	return n.Flags
+}
+
+// Connect looks up the peer, like other@host, with EPMD and sets up a
+// connection to it.
+func (n *Node) Connect(peer string) (*DistConn, error) {
+	i := strings.IndexByte(peer, '@')
+	if i < 0 {
+		return nil, fmt.Errorf("%q is not a valid node name", peer)
+	}
+	name, host := peer[:i], peer[i+1:]
+
+	epmd := n.EPMD
+	if epmd == nil {
+		epmd = &EPMD{Addr: net.JoinHostPort(host, strconv.Itoa(epmdDefaultPort))}
+	}
+
+	entry, err := epmd.PortPlease(name)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(int(entry.Port))), handshakeTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.Handshake(conn, entry.HighestVersion)
+}
+
+// Handshake sets up the distribution on conn as the initiating node. version
+// is the highest version the peer registered with EPMD. The connection is
+// closed if the handshake fails.
+func (n *Node) Handshake(conn net.Conn, version uint16) (*DistConn, error) {
+	h := handshake{node: n, conn: conn, r: NewPacketReader(conn, 2), w: NewPacketWriter(conn, 2)}
+	conn.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	if err := h.initiate(version); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{})
+	return newDistConn(n, conn, h.peer, h.peerFlags, h.peerCreation), nil
+}
+
+// Accept sets up the distribution on conn as the accepting node. The
+// connection is closed if the handshake fails.
+func (n *Node) Accept(conn net.Conn) (*DistConn, error) {
+	h := handshake{node: n, conn: conn, r: NewPacketReader(conn, 2), w: NewPacketWriter(conn, 2)}
+	conn.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	if err := h.accept(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Time{})
+	return newDistConn(n, conn, h.peer, h.peerFlags, h.peerCreation), nil
+}
+
+type handshake struct {
+	node *Node
+	conn net.Conn
+	r    *PacketReader
+	w    *PacketWriter
+
+	peer          string
+	peerFlags     DistFlags
+	peerCreation  uint32
+	challenge     uint32
+	peerChallenge uint32
+}
+
+func (h *handshake) initiate(version uint16) error {
+	flags := h.node.flags()
+
+	var msg bytes.Buffer
+	if version >= highestDistVsn && flags&FlagHandshake23 != 0 {
+		msg.WriteByte(handshakeName6)
+		writeUint64(&msg, uint64(flags))
+		writeUint32(&msg, h.node.Creation)
+		writeUint16(&msg, uint16(len(h.node.Name)))
+	} else {
+		msg.WriteByte(handshakeName5)
+		writeUint16(&msg, lowestDistVsn)
+		writeUint32(&msg, uint32(flags))
+	}
+	msg.WriteString(h.node.Name)
+	sentName6 := msg.Bytes()[0] == handshakeName6
+
+	if err := h.w.WritePacket(msg.Bytes()); err != nil {
+		return err
+	}
+
+	if err := h.readStatus(); err != nil {
+		return err
+	}
+
+	gotName6, err := h.readChallenge()
+	if err != nil {
+		return err
+	}
+
+	if gotName6 && !sentName6 {
+		var complement bytes.Buffer
+		complement.WriteByte(handshakeComplete)
+		writeUint32(&complement, uint32(flags>>32))
+		writeUint32(&complement, h.node.Creation)
+		if err := h.w.WritePacket(complement.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if h.challenge, err = newChallenge(); err != nil {
+		return err
+	}
+
+	var reply bytes.Buffer
+	reply.WriteByte(handshakeReply)
+	writeUint32(&reply, h.challenge)
+	reply.Write(h.digest(h.peerChallenge))
+	if err := h.w.WritePacket(reply.Bytes()); err != nil {
+		return err
+	}
+
+	ack, err := h.r.ReadPacket()
+	if err != nil {
+		return err
+	} else if len(ack) != 1+handshakeDigestLen || ack[0] != handshakeAck {
+		return fmt.Errorf("%v is not a valid challenge ack", ack)
+	} else if !bytes.Equal(ack[1:], h.digest(h.challenge)) {
+		return fmt.Errorf("node %v does not share the cookie", h.peer)
+	}
+
+	return nil
+}
+
+func (h *handshake) readStatus() error {
+	status, err := h.r.ReadPacket()
+	if err != nil {
+		return err
+	} else if len(status) == 0 || status[0] != handshakeStatus {
+		return fmt.Errorf("%v is not a valid handshake status", status)
+	}
+
+	switch string(status[1:]) {
+	case "ok", "ok_simultaneous":
+		return nil
+	case "alive":
+		// we do not replace a connection the peer still has to us
+		h.w.WritePacket([]byte("sfalse"))
+		return fmt.Errorf("the peer is still connected to %v", h.node.Name)
+	default:
+		return fmt.Errorf("the peer refused the connection with status %q", status[1:])
+	}
+}
+
+func (h *handshake) readChallenge() (bool, error) {
+	data, err := h.r.ReadPacket()
+	if err != nil {
+		return false, err
+	} else if len(data) == 0 {
+		return false, fmt.Errorf("received an empty challenge")
+	}
+
+	switch data[0] {
+	case handshakeName5:
+		if len(data) < 11 {
+			return false, fmt.Errorf("%v is not a valid challenge", data)
+		}
+		h.peerFlags = DistFlags(binary.BigEndian.Uint32(data[3:]))
+		h.peerChallenge = binary.BigEndian.Uint32(data[7:])
+		h.peer = string(data[11:])
+		return false, h.checkPeerFlags()
+	case handshakeName6:
+		if len(data) < 19 || len(data) != 19+int(binary.BigEndian.Uint16(data[17:])) {
+			return false, fmt.Errorf("%v is not a valid challenge", data)
+		}
+		h.peerFlags = DistFlags(binary.BigEndian.Uint64(data[1:]))
+		h.peerChallenge = binary.BigEndian.Uint32(data[9:])
+		h.peerCreation = binary.BigEndian.Uint32(data[13:])
+		h.peer = string(data[19:])
+		return true, h.checkPeerFlags()
+	default:
+		return false, fmt.Errorf("%v is not tagging a challenge", data[0])
+	}
+}
+
+func (h *handshake) accept() error {
+	data, err := h.r.ReadPacket()
+	if err != nil {
+		return err
+	} else if len(data) == 0 {
+		return fmt.Errorf("received an empty name")
+	}
+
+	switch data[0] {
+	case handshakeName5:
+		if len(data) < 7 {
+			return fmt.Errorf("%v is not a valid name message", data)
+		}
+		h.peerFlags = DistFlags(binary.BigEndian.Uint32(data[3:]))
+		h.peer = string(data[7:])
+	case handshakeName6:
+		if len(data) < 15 || len(data) != 15+int(binary.BigEndian.Uint16(data[13:])) {
+			return fmt.Errorf("%v is not a valid name message", data)
+		}
+		h.peerFlags = DistFlags(binary.BigEndian.Uint64(data[1:]))
+		h.peerCreation = binary.BigEndian.Uint32(data[9:])
+		h.peer = string(data[15:])
+	default:
+		return fmt.Errorf("%v is not tagging a name message", data[0])
+	}
+
+	if err := h.checkPeerFlags(); err != nil {
+		h.w.WritePacket([]byte("snot_allowed"))
+		return err
+	}
+
+	if err := h.w.WritePacket([]byte("sok")); err != nil {
+		return err
+	}
+
+	if h.challenge, err = newChallenge(); err != nil {
+		return err
+	}
+
+	flags := h.node.flags()
+	sendName6 := h.peerFlags&FlagHandshake23 != 0 && flags&FlagHandshake23 != 0
+
+	var challenge bytes.Buffer
+	if sendName6 {
+		challenge.WriteByte(handshakeName6)
+		writeUint64(&challenge, uint64(flags))
+		writeUint32(&challenge, h.challenge)
+		writeUint32(&challenge, h.node.Creation)
+		writeUint16(&challenge, uint16(len(h.node.Name)))
+	} else {
+		challenge.WriteByte(handshakeName5)
+		writeUint16(&challenge, lowestDistVsn)
+		writeUint32(&challenge, uint32(flags))
+		writeUint32(&challenge, h.challenge)
+	}
+	challenge.WriteString(h.node.Name)
+	if err := h.w.WritePacket(challenge.Bytes()); err != nil {
+		return err
+	}
+
+	if sendName6 && data[0] == handshakeName5 {
+		complement, err := h.r.ReadPacket()
+		if err != nil {
+			return err
+		} else if len(complement) != 9 || complement[0] != handshakeComplete {
+			return fmt.Errorf("%v is not a valid complement", complement)
+		}
+		h.peerFlags |= DistFlags(binary.BigEndian.Uint32(complement[1:])) << 32
+		h.peerCreation = binary.BigEndian.Uint32(complement[5:])
+	}
+
+	reply, err := h.r.ReadPacket()
+	if err != nil {
+		return err
+	} else if len(reply) != 5+handshakeDigestLen || reply[0] != handshakeReply {
+		return fmt.Errorf("%v is not a valid challenge reply", reply)
+	} else if !bytes.Equal(reply[5:], h.digest(h.challenge)) {
+		return fmt.Errorf("node %v does not share the cookie", h.peer)
+	}
+	h.peerChallenge = binary.BigEndian.Uint32(reply[1:])
+
+	return h.w.WritePacket(append([]byte{handshakeAck}, h.digest(h.peerChallenge)...))
+}
+
+func (h *handshake) checkPeerFlags() error {
+	if missing := requiredFlags &^ h.peerFlags; missing != 0 {
+		return fmt.Errorf("node %v lacks the distribution flags %#x", h.peer, uint64(missing))
+	}
+
+	return nil
+}
+
+func (h *handshake) digest(challenge uint32) []byte {
+	sum := md5.Sum([]byte(h.node.Cookie + strconv.FormatUint(uint64(challenge), 10)))
+	return sum[:]
+}
+
+func newChallenge() (uint32, error) {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+
+	return binary.BigEndian.Uint32(b[:]), nil
+}
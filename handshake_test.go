@@ -0,0 +1,207 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"net"
+	"testing"
+	"time"
+)
+
+type handshakeResult struct {
+	conn *erlgo.DistConn
+	err  error
+}
+
+// handshakeNodes connects the nodes over loopback, with a initiating.
+func handshakeNodes(t *testing.T, a, b *erlgo.Node, version uint16) (handshakeResult, handshakeResult) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(`listening failed with "%v".`, err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan handshakeResult)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			accepted <- handshakeResult{nil, err}
+			return
+		}
+		dc, err := b.Accept(conn)
+		accepted <- handshakeResult{dc, err}
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf(`dialing failed with "%v".`, err)
+	}
+	dc, err := a.Handshake(conn, version)
+
+	return handshakeResult{dc, err}, <-accepted
+}
+
+func TestHandshake(t *testing.T) {
+	legacyFlags := erlgo.DefaultFlags &^ erlgo.FlagHandshake23
+
+	for _, test := range []struct {
+		Name         string
+		Version      uint16
+		Flags        erlgo.DistFlags
+		ExpectFlags  erlgo.DistFlags
+		ExpectCreate uint32
+	}{
+		{"version 6", 6, 0, erlgo.DefaultFlags, 1},
+		{"version 5 with complement", 5, 0, erlgo.DefaultFlags, 1},
+		{"version 5 only", 5, legacyFlags, legacyFlags & (1<<32 - 1), 0},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			a := &erlgo.Node{Name: "a@127.0.0.1", Cookie: "secret", Creation: 1, Flags: test.Flags}
+			b := &erlgo.Node{Name: "b@127.0.0.1", Cookie: "secret", Creation: 2}
+
+			ra, rb := handshakeNodes(t, a, b, test.Version)
+			if ra.err != nil || rb.err != nil {
+				t.Fatalf(`handshake failed with "%v" and "%v".`, ra.err, rb.err)
+			}
+			defer ra.conn.Close()
+			defer rb.conn.Close()
+
+			if ra.conn.Peer != "b@127.0.0.1" || rb.conn.Peer != "a@127.0.0.1" {
+				t.Errorf(`peers are %q and %q.`, ra.conn.Peer, rb.conn.Peer)
+			}
+			if rb.conn.PeerFlags != test.ExpectFlags || rb.conn.PeerCreation != test.ExpectCreate {
+				t.Errorf(`acceptor saw flags %#x and creation %v, expected %#x and %v.`,
+					rb.conn.PeerFlags, rb.conn.PeerCreation, test.ExpectFlags, test.ExpectCreate)
+			}
+
+			control := erlgo.Tuple{erlgo.Int64(2), erlgo.Atom(""), erlgo.Atom("echo")}
+			message := erlgo.Binary("hello")
+			if err := ra.conn.Send(control, message); err != nil {
+				t.Fatalf(`sending failed with "%v".`, err)
+			}
+			if c, m, err := rb.conn.Receive(); err != nil {
+				t.Errorf(`receiving failed with "%v".`, err)
+			} else if !c.Matches(control) || !m.Matches(message) {
+				t.Errorf(`received %#v and %#v, expected %#v and %#v.`, c, m, control, message)
+			}
+		})
+	}
+}
+
+func TestHandshakeCookieMismatch(t *testing.T) {
+	a := &erlgo.Node{Name: "a@127.0.0.1", Cookie: "secret"}
+	b := &erlgo.Node{Name: "b@127.0.0.1", Cookie: "other"}
+
+	if ra, rb := handshakeNodes(t, a, b, 6); ra.err == nil || rb.err == nil {
+		t.Errorf(`handshake with different cookies returned "%v" and "%v", expected errors.`, ra.err, rb.err)
+	}
+}
+
+func TestHandshakeMissingFlags(t *testing.T) {
+	a := &erlgo.Node{Name: "a@127.0.0.1", Cookie: "secret", Flags: erlgo.FlagHandshake23 | erlgo.FlagUTF8Atoms}
+	b := &erlgo.Node{Name: "b@127.0.0.1", Cookie: "secret"}
+
+	if ra, rb := handshakeNodes(t, a, b, 6); ra.err == nil || rb.err == nil {
+		t.Errorf(`handshake lacking flags returned "%v" and "%v", expected errors.`, ra.err, rb.err)
+	}
+}
+
+func TestDistConnTicks(t *testing.T) {
+	a := &erlgo.Node{Name: "a@127.0.0.1", Cookie: "secret", TickInterval: 10 * time.Millisecond}
+	b := &erlgo.Node{Name: "b@127.0.0.1", Cookie: "secret", TickInterval: 10 * time.Millisecond}
+
+	ra, rb := handshakeNodes(t, a, b, 6)
+	if ra.err != nil || rb.err != nil {
+		t.Fatalf(`handshake failed with "%v" and "%v".`, ra.err, rb.err)
+	}
+	defer ra.conn.Close()
+	defer rb.conn.Close()
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		ra.conn.Send(erlgo.Atom("late"), nil)
+	}()
+
+	if c, m, err := rb.conn.Receive(); err != nil {
+		t.Errorf(`receiving failed with "%v", expected ticks to keep the connection.`, err)
+	} else if !c.Matches(erlgo.Atom("late")) || m != nil {
+		t.Errorf(`received %#v and %#v, expected late and nil.`, c, m)
+	}
+}
+
+func TestDistConnSilentPeer(t *testing.T) {
+	a := &erlgo.Node{Name: "a@127.0.0.1", Cookie: "secret", TickInterval: time.Hour}
+	b := &erlgo.Node{Name: "b@127.0.0.1", Cookie: "secret", TickInterval: 10 * time.Millisecond}
+
+	ra, rb := handshakeNodes(t, a, b, 6)
+	if ra.err != nil || rb.err != nil {
+		t.Fatalf(`handshake failed with "%v" and "%v".`, ra.err, rb.err)
+	}
+	defer ra.conn.Close()
+	defer rb.conn.Close()
+
+	if data, err := rb.conn.ReadMessage(); err == nil {
+		t.Errorf(`reading from a silent peer returned %v, expected a timeout.`, data)
+	}
+}
+
+func TestNodeConnect(t *testing.T) {
+	fake := newFakeEPMD(t)
+	defer fake.Close()
+
+	epmd := &erlgo.EPMD{Addr: fake.Addr()}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf(`listening failed with "%v".`, err)
+	}
+	defer ln.Close()
+
+	reg, err := epmd.Register(erlgo.NodeEntry{Name: "peer", Port: uint16(ln.Addr().(*net.TCPAddr).Port)})
+	if err != nil {
+		t.Fatalf(`registration failed with "%v".`, err)
+	}
+	defer reg.Close()
+
+	peer := &erlgo.Node{Name: "peer@127.0.0.1", Cookie: "secret", Creation: reg.Creation}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		dc, err := peer.Accept(conn)
+		if err != nil {
+			return
+		}
+		defer dc.Close()
+
+		if c, m, err := dc.Receive(); err == nil {
+			dc.Send(c, erlgo.Tuple{erlgo.Atom("reply"), m})
+		}
+	}()
+
+	node := &erlgo.Node{Name: "gonode@127.0.0.1", Cookie: "secret", EPMD: epmd}
+	dc, err := node.Connect("peer@127.0.0.1")
+	if err != nil {
+		t.Fatalf(`connecting failed with "%v".`, err)
+	}
+	defer dc.Close()
+
+	if dc.PeerCreation != reg.Creation {
+		t.Errorf(`peer has creation %v, expected %v.`, dc.PeerCreation, reg.Creation)
+	}
+
+	if err := dc.Send(erlgo.Atom("ping"), erlgo.Int64(42)); err != nil {
+		t.Fatalf(`sending failed with "%v".`, err)
+	}
+
+	expect := erlgo.Tuple{erlgo.Atom("reply"), erlgo.Int64(42)}
+	if _, m, err := dc.Receive(); err != nil {
+		t.Errorf(`receiving failed with "%v".`, err)
+	} else if !m.Matches(expect) {
+		t.Errorf(`received %#v, expected %#v.`, m, expect)
+	}
+
+	if _, err := node.Connect("unknown@127.0.0.1"); err == nil {
+		t.Errorf(`connecting to an unknown node succeeded, expected an error.`)
+	}
+}
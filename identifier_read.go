@@ -0,0 +1,249 @@
+package erlgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Pid struct {
+	Node     Atom
+	ID       uint32
+	Serial   uint32
+	Creation uint32
+}
+
+func (p Pid) IsInteger() bool {
+	return false
+}
+
+func (p Pid) IsList() bool { return false }
+
+func (p Pid) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+func (p Pid) Matches(other Term) bool {
+	switch o := other.(type) {
+	case Pid:
+		return p == o
+	default:
+		return false
+	}
+}
+
+type Port struct {
+	Node     Atom
+	ID       uint64
+	Creation uint32
+}
+
+func (p Port) IsInteger() bool {
+	return false
+}
+
+func (p Port) IsList() bool { return false }
+
+func (p Port) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+func (p Port) Matches(other Term) bool {
+	switch o := other.(type) {
+	case Port:
+		return p == o
+	default:
+		return false
+	}
+}
+
+// Reference holds the ID words in the order they have on the wire, the first
+// one being the least significant.
+type Reference struct {
+	Node     Atom
+	Creation uint32
+	IDs      []uint32
+}
+
+func (r Reference) IsInteger() bool {
+	return false
+}
+
+func (r Reference) IsList() bool { return false }
+
+func (r Reference) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+func (r Reference) Matches(other Term) bool {
+	switch o := other.(type) {
+	case Reference:
+		if r.Node != o.Node || r.Creation != o.Creation || len(r.IDs) != len(o.IDs) {
+			return false
+		}
+		for i := range r.IDs {
+			if r.IDs[i] != o.IDs[i] {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// readNode decodes the node name, which is encoded as an atom in all the
+// identifier terms.
+func readNode(b ErlExtBinary) (Atom, error) {
+	term, err := decodeRemaining(b)
+	if err != nil {
+		return "", err
+	}
+
+	if node, ok := term.(Atom); ok {
+		return node, nil
+	}
+	return "", fmt.Errorf("%#v is not a valid node name", term)
+}
+
+func decodePid(b ErlExtBinary) (Term, error) {
+	tag, err := b.bs.ReadByte()
+	if err != nil {
+		return nil, err
+	} else if tag != pidExt && tag != newPidExt {
+		return nil, fmt.Errorf("%v is not tagging a pid", tag)
+	}
+
+	node, err := readNode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, err := readCreation(b, tag == newPidExt)
+	if err != nil {
+		return nil, err
+	}
+
+	return Pid{Node: node, ID: uint32(id), Serial: uint32(serial), Creation: creation}, nil
+}
+
+func decodePort(b ErlExtBinary) (Term, error) {
+	tag, err := b.bs.ReadByte()
+	if err != nil {
+		return nil, err
+	} else if tag != portExt && tag != newPortExt && tag != v4PortExt {
+		return nil, fmt.Errorf("%v is not tagging a port", tag)
+	}
+
+	node, err := readNode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var id uint64
+	if tag == v4PortExt {
+		high, err := readInt32(b)
+		if err != nil {
+			return nil, err
+		}
+		low, err := readInt32(b)
+		if err != nil {
+			return nil, err
+		}
+		id = uint64(uint32(high))<<32 | uint64(uint32(low))
+	} else {
+		low, err := readInt32(b)
+		if err != nil {
+			return nil, err
+		}
+		id = uint64(uint32(low))
+	}
+
+	creation, err := readCreation(b, tag != portExt)
+	if err != nil {
+		return nil, err
+	}
+
+	return Port{Node: node, ID: id, Creation: creation}, nil
+}
+
+func decodeReference(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != reference {
+		return nil, fmt.Errorf("%v is not tagging a reference", tag)
+	}
+
+	node, err := readNode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, err := readCreation(b, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return Reference{Node: node, Creation: creation, IDs: []uint32{uint32(id)}}, nil
+}
+
+func decodeNewReference(b ErlExtBinary) (Term, error) {
+	tag, err := b.bs.ReadByte()
+	if err != nil {
+		return nil, err
+	} else if tag != newReferenceExt && tag != newerReferenceExt {
+		return nil, fmt.Errorf("%v is not tagging a new reference", tag)
+	}
+
+	length, err := readUint16(b)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := readNode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, err := readCreation(b, tag == newerReferenceExt)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint32, length)
+	for i := range ids {
+		if id, err := readInt32(b); err != nil {
+			return nil, err
+		} else {
+			ids[i] = uint32(id)
+		}
+	}
+
+	return Reference{Node: node, Creation: creation, IDs: ids}, nil
+}
+
+// readCreation reads either the old single byte creation or the 32 bit one
+// used by the newer tags.
+func readCreation(b ErlExtBinary, wide bool) (uint32, error) {
+	if wide {
+		creation, err := readInt32(b)
+		return uint32(creation), err
+	}
+
+	creation, err := b.bs.ReadByte()
+	return uint32(creation), err
+}
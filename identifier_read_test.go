@@ -0,0 +1,81 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+func identifierData(tag byte, node []byte, rest ...byte) erlgo.ErlExtBinary {
+	data := append([]byte{131, tag}, node...)
+	return erlgo.FromBytes(append(data, rest...))
+}
+
+// nonode@nohost as ATOM_EXT and SMALL_ATOM_UTF8_EXT
+var nodeAtom = []byte{100, 0, 13, 110, 111, 110, 111, 100, 101, 64, 110, 111, 104, 111, 115, 116}
+var nodeSmallAtom = []byte{119, 13, 110, 111, 110, 111, 100, 101, 64, 110, 111, 104, 111, 115, 116}
+
+var identifierTestTable = []struct {
+	Name   string
+	Data   erlgo.ErlExtBinary
+	Expect erlgo.Term
+}{
+	{"<0.42.0>", identifierData(103, nodeAtom, 0, 0, 0, 42, 0, 0, 0, 0, 0), erlgo.Pid{Node: "nonode@nohost", ID: 42}},
+	{"<0.42.1> (new)", identifierData(88, nodeSmallAtom, 0, 0, 0, 42, 0, 0, 0, 1, 0, 0, 0, 3), erlgo.Pid{Node: "nonode@nohost", ID: 42, Serial: 1, Creation: 3}},
+	{"#Port<0.5>", identifierData(102, nodeAtom, 0, 0, 0, 5, 1), erlgo.Port{Node: "nonode@nohost", ID: 5, Creation: 1}},
+	{"#Port<0.5> (new)", identifierData(89, nodeSmallAtom, 0, 0, 0, 5, 0, 1, 0, 0), erlgo.Port{Node: "nonode@nohost", ID: 5, Creation: 65536}},
+	{"#Port<0.4294967301> (v4)", identifierData(120, nodeSmallAtom, 0, 0, 0, 1, 0, 0, 0, 5, 0, 0, 0, 2), erlgo.Port{Node: "nonode@nohost", ID: 1<<32 | 5, Creation: 2}},
+	{"#Ref<0.7>", identifierData(101, nodeAtom, 0, 0, 0, 7, 1), erlgo.Reference{Node: "nonode@nohost", Creation: 1, IDs: []uint32{7}}},
+	{"#Ref<0.3.2.1> (new)", identifierData(114, append([]byte{0, 3}, nodeAtom...), 2, 0, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3), erlgo.Reference{Node: "nonode@nohost", Creation: 2, IDs: []uint32{1, 2, 3}}},
+	{"#Ref<0.3.2.1> (newer)", identifierData(90, append([]byte{0, 3}, nodeSmallAtom...), 0, 0, 0, 2, 0, 0, 0, 1, 0, 0, 0, 2, 0, 0, 0, 3), erlgo.Reference{Node: "nonode@nohost", Creation: 2, IDs: []uint32{1, 2, 3}}},
+}
+
+func TestReadingIdentifiers(t *testing.T) {
+	for _, test := range identifierTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if val, err := test.Data.Decode(); err == nil && !val.Matches(test.Expect) {
+				t.Errorf(`%#v parsed into %#v, expected %#v."`, test.Data, val, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected value %#v.`, test.Data, err, test.Expect)
+			}
+		})
+	}
+}
+
+func TestReadingIdentifierWithInvalidNode(t *testing.T) {
+	if val, err := identifierData(103, []byte{97, 1}, 0, 0, 0, 42, 0, 0, 0, 0, 0).Decode(); err == nil {
+		t.Errorf(`pid with integer node parsed into %#v, expected an error.`, val)
+	}
+}
+
+func TestIdentifierMatches(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		A, B   erlgo.Term
+		Expect bool
+	}{
+		{"same pid", erlgo.Pid{Node: "a@b", ID: 1}, erlgo.Pid{Node: "a@b", ID: 1}, true},
+		{"pid on other node", erlgo.Pid{Node: "a@b", ID: 1}, erlgo.Pid{Node: "a@c", ID: 1}, false},
+		{"pid with other creation", erlgo.Pid{Node: "a@b", ID: 1}, erlgo.Pid{Node: "a@b", ID: 1, Creation: 1}, false},
+		{"same port", erlgo.Port{Node: "a@b", ID: 1}, erlgo.Port{Node: "a@b", ID: 1}, true},
+		{"port and pid", erlgo.Port{Node: "a@b", ID: 1}, erlgo.Pid{Node: "a@b", ID: 1}, false},
+		{"same reference", erlgo.Reference{Node: "a@b", IDs: []uint32{1, 2}}, erlgo.Reference{Node: "a@b", IDs: []uint32{1, 2}}, true},
+		{"reference with other ids", erlgo.Reference{Node: "a@b", IDs: []uint32{1, 2}}, erlgo.Reference{Node: "a@b", IDs: []uint32{1, 3}}, false},
+		{"reference with fewer ids", erlgo.Reference{Node: "a@b", IDs: []uint32{1, 2}}, erlgo.Reference{Node: "a@b", IDs: []uint32{1}}, false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.A.Matches(test.B) != test.Expect {
+				t.Errorf(`%#v.Matches(%#v) returned %v, expected %v.`, test.A, test.B, !test.Expect, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkReadingIdentifiers(b *testing.B) {
+	for _, data := range identifierTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				data.Data.Decode()
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package erlgo
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+func encodePid(buf *bytes.Buffer, p Pid) error {
+	buf.WriteByte(newPidExt)
+	if err := encodeAtom(buf, p.Node); err != nil {
+		return err
+	}
+	writeUint32(buf, p.ID)
+	writeUint32(buf, p.Serial)
+	writeUint32(buf, p.Creation)
+
+	return nil
+}
+
+func encodePort(buf *bytes.Buffer, p Port) error {
+	if p.ID <= math.MaxUint32 {
+		buf.WriteByte(newPortExt)
+	} else {
+		buf.WriteByte(v4PortExt)
+	}
+
+	if err := encodeAtom(buf, p.Node); err != nil {
+		return err
+	}
+
+	if p.ID > math.MaxUint32 {
+		writeUint32(buf, uint32(p.ID>>32))
+	}
+	writeUint32(buf, uint32(p.ID))
+	writeUint32(buf, p.Creation)
+
+	return nil
+}
+
+func encodeReference(buf *bytes.Buffer, r Reference) error {
+	if len(r.IDs) > math.MaxUint16 {
+		return fmt.Errorf("a reference can not have %v ID words", len(r.IDs))
+	}
+
+	buf.WriteByte(newerReferenceExt)
+	writeUint16(buf, uint16(len(r.IDs)))
+	if err := encodeAtom(buf, r.Node); err != nil {
+		return err
+	}
+	writeUint32(buf, r.Creation)
+	for _, id := range r.IDs {
+		writeUint32(buf, id)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var identifierWriteTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect []byte
+}{
+	{"<0.42.1>", erlgo.Pid{Node: "nonode@nohost", ID: 42, Serial: 1, Creation: 3}, append(append([]byte{131, 88}, nodeSmallAtom...), 0, 0, 0, 42, 0, 0, 0, 1, 0, 0, 0, 3)},
+	{"#Port<0.5>", erlgo.Port{Node: "nonode@nohost", ID: 5, Creation: 1}, append(append([]byte{131, 89}, nodeSmallAtom...), 0, 0, 0, 5, 0, 0, 0, 1)},
+	{"#Port<0.4294967301>", erlgo.Port{Node: "nonode@nohost", ID: 1<<32 | 5, Creation: 2}, append(append([]byte{131, 120}, nodeSmallAtom...), 0, 0, 0, 1, 0, 0, 0, 5, 0, 0, 0, 2)},
+	{"#Ref<0.2.1>", erlgo.Reference{Node: "nonode@nohost", Creation: 2, IDs: []uint32{1, 2}}, append(append([]byte{131, 90, 0, 2}, nodeSmallAtom...), 0, 0, 0, 2, 0, 0, 0, 1, 0, 0, 0, 2)},
+}
+
+func TestWritingIdentifiers(t *testing.T) {
+	for _, test := range identifierWriteTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil && !bytes.Equal(data, test.Expect) {
+				t.Errorf(`%#v encoded into %v, expected %v.`, test.Term, data, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %v.`, test.Term, err, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkWritingIdentifiers(b *testing.B) {
+	for _, data := range identifierWriteTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Encode(data.Term)
+			}
+		})
+	}
+}
@@ -131,6 +131,8 @@ func decodeSmallBigInteger(b ErlExtBinary) (Term, error) {
 
 	if byteCount, err := b.bs.ReadByte(); err != nil {
 		return nil, err
+	} else if err := b.state.checkBigIntBytes(uint32(byteCount)); err != nil {
+		return nil, err
 	} else {
 		res := int64(0)
 		mul := int64(1)
@@ -202,6 +204,8 @@ func decodeLargeBigInteger(b ErlExtBinary) (Term, error) {
 	byteCount, err := readInt32(b)
 	if err != nil {
 		return nil, err
+	} else if err := b.state.checkBigIntBytes(uint32(byteCount)); err != nil {
+		return nil, err
 	}
 
 	signum, err := b.bs.ReadByte()
@@ -0,0 +1,73 @@
+package erlgo
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+)
+
+func encodeInt64(buf *bytes.Buffer, i Int64) error {
+	switch {
+	case i >= 0 && i <= math.MaxUint8:
+		buf.WriteByte(smallIntegerExt)
+		buf.WriteByte(byte(i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		buf.WriteByte(integerExt)
+		writeUint32(buf, uint32(i))
+	default:
+		// for math.MinInt64 the negation overflows, but converting the result
+		// to uint64 still yields the correct magnitude
+		magnitude, signum := uint64(i), byte(0)
+		if i < 0 {
+			magnitude, signum = uint64(-i), 1
+		}
+
+		digits := make([]byte, 0, 8)
+		for ; magnitude > 0; magnitude >>= 8 {
+			digits = append(digits, byte(magnitude))
+		}
+
+		writeBigDigits(buf, signum, digits)
+	}
+
+	return nil
+}
+
+func encodeIntBig(buf *bytes.Buffer, i IntBig) error {
+	// the zero value of big.Int is 0 as well
+	if i.Int == nil {
+		return encodeInt64(buf, 0)
+	}
+
+	if v, ok := i.Int64(); ok {
+		return encodeInt64(buf, Int64(v))
+	}
+
+	signum := byte(0)
+	if i.Sign() < 0 {
+		signum = 1
+	}
+
+	// big.Int gives us big endian bytes of the absolute value, while erlang
+	// expects them to be little endian
+	digits := new(big.Int).Abs(i.Int).Bytes()
+	for l, r := 0, len(digits)-1; l < r; l, r = l+1, r-1 {
+		digits[l], digits[r] = digits[r], digits[l]
+	}
+
+	writeBigDigits(buf, signum, digits)
+
+	return nil
+}
+
+func writeBigDigits(buf *bytes.Buffer, signum byte, digits []byte) {
+	if len(digits) <= math.MaxUint8 {
+		buf.WriteByte(smallBigIntegerExt)
+		buf.WriteByte(byte(len(digits)))
+	} else {
+		buf.WriteByte(largeBigIntegerExt)
+		writeUint32(buf, uint32(len(digits)))
+	}
+	buf.WriteByte(signum)
+	buf.Write(digits)
+}
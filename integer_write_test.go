@@ -0,0 +1,53 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"math"
+	"math/big"
+	"testing"
+)
+
+var integerWriteTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect []byte
+}{
+	{"0", erlgo.Int64(0), []byte{131, 97, 0}},
+	{"255", erlgo.Int64(255), []byte{131, 97, 255}},
+	{"256", erlgo.Int64(256), []byte{131, 98, 0, 0, 1, 0}},
+	{"-1", erlgo.Int64(-1), []byte{131, 98, 255, 255, 255, 255}},
+	{"2147483647", erlgo.Int64(2147483647), []byte{131, 98, 127, 255, 255, 255}},
+	{"-2147483648", erlgo.Int64(-2147483648), []byte{131, 98, 128, 0, 0, 0}},
+	{"2147483648", erlgo.Int64(2147483648), []byte{131, 110, 4, 0, 0, 0, 0, 128}},
+	{"-2147483649", erlgo.Int64(-2147483649), []byte{131, 110, 4, 1, 1, 0, 0, 128}},
+	{"9223372036854775807", erlgo.Int64(math.MaxInt64), []byte{131, 110, 8, 0, 255, 255, 255, 255, 255, 255, 255, 127}},
+	{"-9223372036854775808", erlgo.Int64(math.MinInt64), []byte{131, 110, 8, 1, 0, 0, 0, 0, 0, 0, 0, 128}},
+	{"zero value (big)", erlgo.IntBig{}, []byte{131, 97, 0}},
+	{"1 (big)", erlgo.IntBig{big.NewInt(1)}, []byte{131, 97, 1}},
+	{"18446744073709551616", erlgo.IntBig{plusNine}, []byte{131, 110, 9, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}},
+	{"-18446744073709551616", erlgo.IntBig{minusNine}, []byte{131, 110, 9, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1}},
+	{"veryBig * 256", erlgo.IntBig{plusVeryBigMulTwoFiveSix}, append([]byte{131, 111, 0, 0, 1, 0, 0}, append(make([]byte, 255), 1)...)},
+}
+
+func TestWritingIntegers(t *testing.T) {
+	for _, test := range integerWriteTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil && !bytes.Equal(data, test.Expect) {
+				t.Errorf(`%#v encoded into %v, expected %v.`, test.Term, data, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %v.`, test.Term, err, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkWritingIntegers(b *testing.B) {
+	for _, data := range integerWriteTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Encode(data.Term)
+			}
+		})
+	}
+}
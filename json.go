@@ -0,0 +1,609 @@
+package erlgo
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ToJSON converts t into JSON that FromJSON converts back into an equal term.
+//
+// Integers up to 2^53 become numbers, floats become numbers that always have
+// a fraction or an exponent, and the atoms `true`, `false` and `null` become
+// the JSON literals. Binaries holding valid UTF-8 become strings and proper
+// lists become arrays. Maps whose keys are all such binaries, none starting
+// with `$`, become objects. Everything else becomes an object with a single
+// key naming its type:
+//
+//	{"$atom": "ok"}
+//	{"$int": "123456789012345678901"}
+//	{"$binary": "<base64>"}
+//	{"$bitstring": {"bytes": "<base64>", "bits": 3}}
+//	{"$tuple": [...]}
+//	{"$improper": {"elements": [...], "tail": ...}}
+//	{"$map": [[key, value], ...]}
+//	{"$pid": {"node": "a@b", "id": 42, "serial": 0, "creation": 1}}
+//	{"$port": {"node": "a@b", "id": 7, "creation": 1}}
+//	{"$ref": {"node": "a@b", "ids": [1, 2, 3], "creation": 1}}
+//	{"$fun": {"module": "m", "arity": 1, "uniq": "<hex>", "index": 0, ...}}
+//	{"$export": {"module": "m", "function": "f", "arity": 1}}
+func ToJSON(t Term) ([]byte, error) {
+	v, err := jsonValue(t, false)
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSON(v)
+}
+
+// ToFriendlyJSON converts t into JSON that is easy to read, but cannot be
+// converted back. Atoms, printable lists and pids and the like become
+// strings, tuples become arrays, and proplists and maps become objects.
+// The atoms `undefined` and `nil` become null.
+func ToFriendlyJSON(t Term) ([]byte, error) {
+	v, err := jsonValue(t, true)
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSON(v)
+}
+
+// marshalJSON does not escape HTML, as pids and strings would be hard to read.
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+const maxJSONInt = 1 << 53
+
+func tagged(tag string, v interface{}) map[string]interface{} {
+	return map[string]interface{}{tag: v}
+}
+
+func jsonValue(t Term, friendly bool) (interface{}, error) {
+	switch v := t.(type) {
+	case Int64:
+		if !friendly && (v > maxJSONInt || v < -maxJSONInt) {
+			return tagged("$int", strconv.FormatInt(int64(v), 10)), nil
+		}
+		return json.Number(strconv.FormatInt(int64(v), 10)), nil
+	case IntBig:
+		if !friendly && (!v.Int.IsInt64() || v.Int.Int64() > maxJSONInt || v.Int.Int64() < -maxJSONInt) {
+			return tagged("$int", v.Int.String()), nil
+		}
+		return json.Number(v.Int.String()), nil
+	case Float:
+		f := float64(v)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("erlgo: float %v has no JSON representation", f)
+		}
+		s := strconv.FormatFloat(f, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		return json.Number(s), nil
+	case Atom:
+		return jsonAtom(v, friendly), nil
+	case Binary:
+		if utf8.Valid(v) {
+			return string(v), nil
+		} else if friendly {
+			return base64.StdEncoding.EncodeToString(v), nil
+		}
+		return tagged("$binary", base64.StdEncoding.EncodeToString(v)), nil
+	case BitString:
+		if friendly {
+			return v.String(), nil
+		}
+		return tagged("$bitstring", map[string]interface{}{
+			"bytes": base64.StdEncoding.EncodeToString(v.Bytes),
+			"bits":  v.Bits,
+		}), nil
+	case Tuple:
+		elems, err := jsonValues(v, friendly)
+		if err != nil || friendly {
+			return elems, err
+		}
+		return tagged("$tuple", elems), nil
+	case Nil:
+		return []interface{}{}, nil
+	case Cons:
+		return jsonList(v, friendly)
+	case Map:
+		return jsonMap(v, friendly)
+	case Pid:
+		if friendly {
+			return v.String(), nil
+		}
+		return tagged("$pid", jsonPid{Node: string(v.Node), ID: v.ID, Serial: v.Serial, Creation: v.Creation}), nil
+	case Port:
+		if friendly {
+			return v.String(), nil
+		}
+		return tagged("$port", jsonPort{Node: string(v.Node), ID: v.ID, Creation: v.Creation}), nil
+	case Reference:
+		if friendly {
+			return v.String(), nil
+		}
+		return tagged("$ref", jsonReference{Node: string(v.Node), IDs: v.IDs, Creation: v.Creation}), nil
+	case Fun:
+		if friendly {
+			return v.String(), nil
+		}
+		return jsonFunValue(v)
+	case Export:
+		if friendly {
+			return v.String(), nil
+		}
+		return tagged("$export", jsonExport{Module: string(v.Module), Function: string(v.Function), Arity: v.Arity}), nil
+	default:
+		return nil, &UnsupportedTypeError{reflect.TypeOf(t)}
+	}
+}
+
+func jsonAtom(a Atom, friendly bool) interface{} {
+	switch {
+	case a == True:
+		return true
+	case a == False:
+		return false
+	case a == "null":
+		return nil
+	case friendly && (a == "undefined" || a == "nil"):
+		return nil
+	case friendly:
+		return string(a)
+	default:
+		return tagged("$atom", string(a))
+	}
+}
+
+func jsonValues(terms []Term, friendly bool) ([]interface{}, error) {
+	values := make([]interface{}, len(terms))
+	for i, t := range terms {
+		v, err := jsonValue(t, friendly)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func jsonList(c Cons, friendly bool) (interface{}, error) {
+	elems, tail := c.Elements()
+
+	if friendly {
+		if _, ok := tail.(Nil); ok {
+			if s, ok := printableUnicode(elems); ok {
+				return s, nil
+			} else if obj, ok := proplistObject(elems); ok {
+				return jsonObject(obj, friendly)
+			}
+		}
+	}
+
+	values, err := jsonValues(elems, friendly)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := tail.(Nil); ok {
+		return values, nil
+	}
+
+	tailValue, err := jsonValue(tail, friendly)
+	if err != nil || friendly {
+		return append(values, tailValue), err
+	}
+	return tagged("$improper", map[string]interface{}{"elements": values, "tail": tailValue}), nil
+}
+
+func printableUnicode(elems []Term) (string, bool) {
+	runes := make([]rune, len(elems))
+	for i, elem := range elems {
+		c, ok := elem.(Int64)
+		if !ok || !isPrintable(int64(c)) && !(c > 255 && c <= maxCodePoint && (c < 0xd800 || c > 0xdfff)) {
+			return "", false
+		}
+		runes[i] = rune(c)
+	}
+	return string(runes), true
+}
+
+// proplistObject returns the pairs of a proplist, whose elements are all
+// tuples of a key and a value or atoms, which are short for {Atom, true}. A
+// list of atoms only is not taken for a proplist. The first value of a key
+// wins, like in proplists:get_value/2.
+func proplistObject(elems []Term) ([]Pair, bool) {
+	pairs := make([]Pair, 0, len(elems))
+	tuples := false
+	for _, elem := range elems {
+		switch e := elem.(type) {
+		case Atom:
+			pairs = append(pairs, Pair{Key: e, Value: True})
+		case Tuple:
+			if len(e) != 2 {
+				return nil, false
+			}
+			if _, ok := friendlyKey(e[0]); !ok {
+				return nil, false
+			}
+			pairs = append(pairs, Pair{Key: e[0], Value: e[1]})
+			tuples = true
+		default:
+			return nil, false
+		}
+	}
+	return pairs, tuples
+}
+
+// friendlyKey returns the object key for keys that read naturally as one.
+func friendlyKey(t Term) (string, bool) {
+	switch k := t.(type) {
+	case Atom:
+		return string(k), true
+	case Binary:
+		return string(k), utf8.Valid(k)
+	default:
+		return "", false
+	}
+}
+
+func jsonObject(pairs []Pair, friendly bool) (interface{}, error) {
+	obj := make(map[string]interface{}, len(pairs))
+	for _, p := range pairs {
+		key, ok := friendlyKey(p.Key)
+		if !ok {
+			key = fmt.Sprint(p.Key)
+		}
+		if _, ok := obj[key]; ok {
+			continue
+		}
+
+		v, err := jsonValue(p.Value, friendly)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = v
+	}
+	return obj, nil
+}
+
+func jsonMap(m Map, friendly bool) (interface{}, error) {
+	pairs := m.SortedPairs()
+	plain := true
+	for _, p := range pairs {
+		if k, ok := p.Key.(Binary); !ok || !utf8.Valid(k) || bytes.HasPrefix(k, []byte("$")) {
+			plain = false
+		}
+	}
+	if plain || friendly {
+		return jsonObject(pairs, friendly)
+	}
+
+	entries := make([]interface{}, len(pairs))
+	for i, p := range pairs {
+		kv, err := jsonValues([]Term{p.Key, p.Value}, friendly)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = kv
+	}
+	return tagged("$map", entries), nil
+}
+
+type jsonPid struct {
+	Node     string `json:"node"`
+	ID       uint32 `json:"id"`
+	Serial   uint32 `json:"serial"`
+	Creation uint32 `json:"creation"`
+}
+
+type jsonPort struct {
+	Node     string `json:"node"`
+	ID       uint64 `json:"id"`
+	Creation uint32 `json:"creation"`
+}
+
+type jsonReference struct {
+	Node     string   `json:"node"`
+	IDs      []uint32 `json:"ids"`
+	Creation uint32   `json:"creation"`
+}
+
+type jsonExport struct {
+	Module   string `json:"module"`
+	Function string `json:"function"`
+	Arity    uint8  `json:"arity"`
+}
+
+// jsonFun holds the free variables as raw JSON, as they are terms themselves.
+type jsonFun struct {
+	Module   string            `json:"module"`
+	Arity    uint8             `json:"arity"`
+	Uniq     string            `json:"uniq"`
+	Index    uint32            `json:"index"`
+	OldIndex uint32            `json:"old_index"`
+	OldUniq  uint32            `json:"old_uniq"`
+	Pid      jsonPid           `json:"pid"`
+	Free     []json.RawMessage `json:"free"`
+	Legacy   bool              `json:"legacy"`
+}
+
+func jsonFunValue(f Fun) (interface{}, error) {
+	jf := jsonFun{
+		Module:   string(f.Module),
+		Arity:    f.Arity,
+		Uniq:     hex.EncodeToString(f.Uniq[:]),
+		Index:    f.Index,
+		OldIndex: f.OldIndex,
+		OldUniq:  f.OldUniq,
+		Pid:      jsonPid{Node: string(f.Pid.Node), ID: f.Pid.ID, Serial: f.Pid.Serial, Creation: f.Pid.Creation},
+		Free:     make([]json.RawMessage, len(f.Free)),
+	}
+	for i, t := range f.Free {
+		data, err := ToJSON(t)
+		if err != nil {
+			return nil, err
+		}
+		jf.Free[i] = data
+	}
+	return tagged("$fun", jf), nil
+}
+
+// FromJSON converts JSON written by ToJSON back into a term. Other JSON is
+// converted as well, numbers become integers or floats, strings become
+// binaries, arrays become lists and objects become maps with binary keys.
+func FromJSON(data []byte) (Term, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("erlgo: empty JSON")
+	}
+
+	switch data[0] {
+	case '{':
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, err
+		}
+		return termFromObject(obj)
+	case '[':
+		var arr []json.RawMessage
+		if err := json.Unmarshal(data, &arr); err != nil {
+			return nil, err
+		}
+		elems, err := termsFromJSON(arr)
+		if err != nil {
+			return nil, err
+		}
+		return NewListFromTerms(elems), nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		return Binary(s), nil
+	case 't', 'f', 'n':
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		switch v {
+		case true:
+			return True, nil
+		case false:
+			return False, nil
+		default:
+			return Atom("null"), nil
+		}
+	default:
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return nil, err
+		}
+		return termFromNumber(string(n))
+	}
+}
+
+func termFromNumber(s string) (Term, error) {
+	if strings.ContainsAny(s, ".eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("erlgo: bad JSON float %v", s)
+		}
+		return Float(f), nil
+	}
+
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("erlgo: bad JSON integer %v", s)
+	}
+	return bigToTerm(i), nil
+}
+
+func termsFromJSON(arr []json.RawMessage) ([]Term, error) {
+	terms := make([]Term, len(arr))
+	for i, raw := range arr {
+		t, err := FromJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		terms[i] = t
+	}
+	return terms, nil
+}
+
+func termFromObject(obj map[string]json.RawMessage) (Term, error) {
+	if len(obj) == 1 {
+		for tag, raw := range obj {
+			if strings.HasPrefix(tag, "$") {
+				return termFromTagged(tag, raw)
+			}
+		}
+	}
+
+	mb := newMapBuilder(len(obj))
+	for key, raw := range obj {
+		v, err := FromJSON(raw)
+		if err != nil {
+			return nil, err
+		}
+		mb.put(Binary(key), v)
+	}
+	return mb.result(), nil
+}
+
+func termFromTagged(tag string, raw json.RawMessage) (Term, error) {
+	bad := func(err error) error {
+		return fmt.Errorf("erlgo: bad JSON %v: %v", tag, err)
+	}
+
+	switch tag {
+	case "$atom":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, bad(err)
+		}
+		return Atom(s), nil
+	case "$int":
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, bad(err)
+		}
+		return termFromNumber(s)
+	case "$binary":
+		var data []byte
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, bad(err)
+		}
+		return Binary(data), nil
+	case "$bitstring":
+		var bs struct {
+			Bytes []byte `json:"bytes"`
+			Bits  uint8  `json:"bits"`
+		}
+		if err := json.Unmarshal(raw, &bs); err != nil {
+			return nil, bad(err)
+		}
+		if len(bs.Bytes) == 0 || bs.Bits < 1 || bs.Bits > 8 {
+			return nil, bad(fmt.Errorf("%v bits used of %v bytes", bs.Bits, len(bs.Bytes)))
+		}
+		return BitString{Bytes: bs.Bytes, Bits: bs.Bits}, nil
+	case "$tuple":
+		var arr []json.RawMessage
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return nil, bad(err)
+		}
+		elems, err := termsFromJSON(arr)
+		if err != nil {
+			return nil, err
+		}
+		return Tuple(elems), nil
+	case "$improper":
+		var l struct {
+			Elements []json.RawMessage `json:"elements"`
+			Tail     json.RawMessage   `json:"tail"`
+		}
+		if err := json.Unmarshal(raw, &l); err != nil {
+			return nil, bad(err)
+		}
+		elems, err := termsFromJSON(l.Elements)
+		if err != nil {
+			return nil, err
+		}
+		tail, err := FromJSON(l.Tail)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := tail.(List); !ok && len(elems) == 0 {
+			return nil, bad(fmt.Errorf("tail %v without elements", tail))
+		}
+		return newListWithTail(elems, tail), nil
+	case "$map":
+		var entries [][2]json.RawMessage
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, bad(err)
+		}
+		mb := newMapBuilder(len(entries))
+		for _, entry := range entries {
+			kv, err := termsFromJSON(entry[:])
+			if err != nil {
+				return nil, err
+			}
+			if !mb.put(kv[0], kv[1]) {
+				return nil, bad(fmt.Errorf("duplicate key %v", kv[0]))
+			}
+		}
+		return mb.result(), nil
+	case "$pid":
+		var p jsonPid
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, bad(err)
+		}
+		return Pid{Node: Atom(p.Node), ID: p.ID, Serial: p.Serial, Creation: p.Creation}, nil
+	case "$port":
+		var p jsonPort
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, bad(err)
+		}
+		return Port{Node: Atom(p.Node), ID: p.ID, Creation: p.Creation}, nil
+	case "$ref":
+		var r jsonReference
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, bad(err)
+		}
+		return Reference{Node: Atom(r.Node), IDs: r.IDs, Creation: r.Creation}, nil
+	case "$export":
+		var e jsonExport
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, bad(err)
+		}
+		return Export{Module: Atom(e.Module), Function: Atom(e.Function), Arity: e.Arity}, nil
+	case "$fun":
+		return funFromJSON(raw, bad)
+	default:
+		return nil, fmt.Errorf("erlgo: unknown JSON tag %v", tag)
+	}
+}
+
+func funFromJSON(raw json.RawMessage, bad func(error) error) (Term, error) {
+	var jf jsonFun
+	if err := json.Unmarshal(raw, &jf); err != nil {
+		return nil, bad(err)
+	}
+
+	f := Fun{
+		Module:   Atom(jf.Module),
+		Arity:    jf.Arity,
+		Index:    jf.Index,
+		OldIndex: jf.OldIndex,
+		OldUniq:  jf.OldUniq,
+		Pid:      Pid{Node: Atom(jf.Pid.Node), ID: jf.Pid.ID, Serial: jf.Pid.Serial, Creation: jf.Pid.Creation},
+		Legacy:   jf.Legacy,
+	}
+
+	uniq, err := hex.DecodeString(jf.Uniq)
+	if err != nil || len(uniq) != len(f.Uniq) {
+		return nil, bad(fmt.Errorf("uniq %q is not 16 bytes of hex", jf.Uniq))
+	}
+	copy(f.Uniq[:], uniq)
+
+	if f.Free, err = termsFromJSON(jf.Free); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
@@ -0,0 +1,164 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/NobbZ/erlgo"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var jsonTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect string
+}{
+	{"integer", erlgo.Int64(42), `42`},
+	{"large integer", erlgo.Int64(1<<53 + 1), `{"$int":"9007199254740993"}`},
+	{"big integer", erlgo.IntBig{new(big.Int).Lsh(big.NewInt(1), 70)}, `{"$int":"1180591620717411303424"}`},
+	{"float", erlgo.Float(1.5), `1.5`},
+	{"integral float", erlgo.Float(2), `2.0`},
+	{"float exponent", erlgo.Float(1e300), `1e+300`},
+	{"true", erlgo.True, `true`},
+	{"null", erlgo.Atom("null"), `null`},
+	{"atom", erlgo.Atom("ok"), `{"$atom":"ok"}`},
+	{"binary", erlgo.Binary("hello"), `"hello"`},
+	{"binary not utf8", erlgo.Binary{0xff, 0}, `{"$binary":"/wA="}`},
+	{"bitstring", erlgo.BitString{Bytes: []byte{1, 0xa0}, Bits: 3}, `{"$bitstring":{"bits":3,"bytes":"AaA="}}`},
+	{"tuple", erlgo.Tuple{erlgo.Atom("ok"), erlgo.Int64(1)}, `{"$tuple":[{"$atom":"ok"},1]}`},
+	{"nil", erlgo.Nil{}, `[]`},
+	{"list", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Binary("a")}), `[1,"a"]`},
+	{"improper list", erlgo.NewCons(erlgo.Int64(1), erlgo.Int64(2)), `{"$improper":{"elements":[1],"tail":2}}`},
+	{"binary keyed map", erlgo.NewMap(erlgo.Pair{Key: erlgo.Binary("a"), Value: erlgo.Int64(1)}), `{"a":1}`},
+	{"atom keyed map", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)}), `{"$map":[[{"$atom":"a"},1]]}`},
+	{"dollar keyed map", erlgo.NewMap(erlgo.Pair{Key: erlgo.Binary("$atom"), Value: erlgo.Int64(1)}), `{"$map":[["$atom",1]]}`},
+	{"pid", erlgo.Pid{Node: "a@b", ID: 42, Creation: 1}, `{"$pid":{"node":"a@b","id":42,"serial":0,"creation":1}}`},
+	{"port", erlgo.Port{Node: "a@b", ID: 7, Creation: 1}, `{"$port":{"node":"a@b","id":7,"creation":1}}`},
+	{"reference", erlgo.Reference{Node: "a@b", IDs: []uint32{1, 2, 3}, Creation: 1}, `{"$ref":{"node":"a@b","ids":[1,2,3],"creation":1}}`},
+	{"export", erlgo.Export{Module: "lists", Function: "map", Arity: 2}, `{"$export":{"module":"lists","function":"map","arity":2}}`},
+}
+
+func TestToJSON(t *testing.T) {
+	for _, test := range jsonTestTable {
+		if data, err := erlgo.ToJSON(test.Term); err != nil {
+			t.Errorf(`%v: encountered error "%v", expected %v.`, test.Name, err, test.Expect)
+		} else if string(data) != test.Expect {
+			t.Errorf(`%v converted into %s, expected %v.`, test.Name, data, test.Expect)
+		}
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	fun := erlgo.Fun{
+		Module: "erl_eval", Arity: 1, Uniq: [16]byte{1, 2, 3}, Index: 5, OldIndex: 6, OldUniq: 7,
+		Pid:  erlgo.Pid{Node: "a@b", ID: 1},
+		Free: []erlgo.Term{erlgo.Atom("x"), erlgo.Int64(1)},
+	}
+	terms := []erlgo.Term{fun, erlgo.Tuple{erlgo.Float(1), erlgo.Int64(1)}}
+	for _, test := range jsonTestTable {
+		terms = append(terms, test.Term)
+	}
+
+	for _, term := range terms {
+		data, err := erlgo.ToJSON(term)
+		if err != nil {
+			t.Errorf(`converting %v encountered error "%v".`, term, err)
+		} else if back, err := erlgo.FromJSON(data); err != nil {
+			t.Errorf(`converting %s back encountered error "%v", expected %v.`, data, err, term)
+		} else if !erlgo.ExactEqual(back, term) {
+			t.Errorf(`%s converted back into %v, expected %v.`, data, back, term)
+		}
+	}
+}
+
+func TestFromForeignJSON(t *testing.T) {
+	for _, test := range []struct {
+		JSON   string
+		Expect erlgo.Term
+	}{
+		{`{"name": "x", "tags": ["a", null], "size": 1.5, "ok": false}`, erlgo.NewMap(
+			erlgo.Pair{Key: erlgo.Binary("name"), Value: erlgo.Binary("x")},
+			erlgo.Pair{Key: erlgo.Binary("tags"), Value: erlgo.NewListFromTerms([]erlgo.Term{erlgo.Binary("a"), erlgo.Atom("null")})},
+			erlgo.Pair{Key: erlgo.Binary("size"), Value: erlgo.Float(1.5)},
+			erlgo.Pair{Key: erlgo.Binary("ok"), Value: erlgo.False})},
+		{`1180591620717411303424`, erlgo.IntBig{new(big.Int).Lsh(big.NewInt(1), 70)}},
+		{`  -7 `, erlgo.Int64(-7)},
+		{`1E3`, erlgo.Float(1000)},
+	} {
+		if term, err := erlgo.FromJSON([]byte(test.JSON)); err != nil {
+			t.Errorf(`%v: encountered error "%v", expected %v.`, test.JSON, err, test.Expect)
+		} else if !term.Matches(test.Expect) {
+			t.Errorf(`%v converted into %v, expected %v.`, test.JSON, term, test.Expect)
+		}
+	}
+}
+
+func TestFromLargeJSON(t *testing.T) {
+	var object, tagged bytes.Buffer
+	object.WriteString(`{`)
+	tagged.WriteString(`{"$map": [`)
+	for i := 0; i < 40000; i++ {
+		if i > 0 {
+			object.WriteString(`,`)
+			tagged.WriteString(`,`)
+		}
+		fmt.Fprintf(&object, `"%v": %v`, i, i)
+		fmt.Fprintf(&tagged, `[%v, %v]`, i, i)
+	}
+	object.WriteString(`}`)
+	tagged.WriteString(`]}`)
+
+	for _, data := range [][]byte{object.Bytes(), tagged.Bytes()} {
+		start := time.Now()
+		if term, err := erlgo.FromJSON(data); err != nil {
+			t.Errorf(`encountered error "%v", expected a map.`, err)
+		} else if m, ok := term.(erlgo.Map); !ok || m.Len() != 40000 {
+			t.Errorf(`converted into a %T, expected a map of 40000 pairs.`, term)
+		}
+		if elapsed := time.Since(start); elapsed > 10*time.Second {
+			t.Errorf(`converting %v bytes took %v.`, len(data), elapsed)
+		}
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	if data, err := erlgo.ToJSON(erlgo.Float(math.NaN())); err == nil {
+		t.Errorf(`NaN converted into %s, expected an error.`, data)
+	}
+
+	for _, data := range []string{
+		``, `{`, `{"$unknown": 1}`, `{"$atom": 1}`, `{"$fun": {"uniq": "00"}}`, `nope`,
+		`{"$map": [[1, "a"], [1, "b"]]}`,
+		`{"$improper": {"elements": [], "tail": 1}}`,
+		`{"$bitstring": {"bytes": "", "bits": 3}}`,
+		`{"$bitstring": {"bytes": "AQ==", "bits": 0}}`,
+		`{"$bitstring": {"bytes": "AQ==", "bits": 9}}`,
+	} {
+		if term, err := erlgo.FromJSON([]byte(data)); err == nil {
+			t.Errorf(`%v converted into %v, expected an error.`, data, term)
+		}
+	}
+}
+
+func TestToFriendlyJSON(t *testing.T) {
+	config, _ := erlgo.ParseTerm(`[{kernel, [{logger_level, info}, {apps, [kernel, stdlib]}]},
+		{demo, [{port, 8080}, {name, "demo"}, {peer, undefined}, {pair, {1, 2}}, verbose, {tags, #{<<"a">> => 1}}]}]`)
+	expect := `{"demo":{"name":"demo","pair":[1,2],"peer":null,"port":8080,"tags":{"a":1},"verbose":true},` +
+		`"kernel":{"apps":["kernel","stdlib"],"logger_level":"info"}}`
+
+	if data, err := erlgo.ToFriendlyJSON(config); err != nil {
+		t.Errorf(`encountered error "%v", expected %v.`, err, expect)
+	} else if string(data) != expect {
+		t.Errorf(`converted into %s, expected %v.`, data, expect)
+	}
+
+	pids := erlgo.NewMap(erlgo.Pair{Key: erlgo.Int64(1), Value: erlgo.Pid{Node: "a@b", ID: 42}})
+	expect = `{"1":"<0.42.0>"}`
+	if data, err := erlgo.ToFriendlyJSON(pids); err != nil {
+		t.Errorf(`encountered error "%v", expected %v.`, err, expect)
+	} else if string(data) != expect {
+		t.Errorf(`converted into %s, expected %v.`, data, expect)
+	}
+}
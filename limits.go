@@ -0,0 +1,138 @@
+package erlgo
+
+import "fmt"
+
+// DecoderOptions limit the resources the decoding of a single term may use, so
+// that untrusted input can be decoded safely. A limit of zero means there is
+// no limit. Even without limits, the memory allocated for a term grows with
+// the input actually read and not with the sizes its headers announce.
+type DecoderOptions struct {
+	// MaxDepth limits how deeply terms may be nested, a term which is not
+	// contained in another one is at depth 1.
+	MaxDepth int
+
+	// MaxTerms limits the number of terms, including all nested ones.
+	MaxTerms int
+
+	// MaxBigIntBytes limits the number of digit bytes of big integers.
+	MaxBigIntBytes int
+
+	// MaxBinarySize limits the number of bytes of binaries and bitstrings.
+	MaxBinarySize int
+
+	// MaxDecompressedSize limits the size a compressed term may declare.
+	MaxDecompressedSize int
+
+	// Safe rejects funs, exports and all atoms not in KnownAtoms, like
+	// `binary_to_term(B, [safe])` does for atoms not existing yet.
+	Safe       bool
+	KnownAtoms AtomRegistry
+}
+
+// LimitError reports a term exceeding one of the DecoderOptions.
+type LimitError struct {
+	// Limit is the name of the exceeded option, like "MaxDepth".
+	Limit string
+	Max   int
+	Value uint64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("term exceeds %v of %v with %v", e.Limit, e.Max, e.Value)
+}
+
+// decodeState is shared by all the copies of an ErlExtBinary, to track the
+// path to and the resources used by the term being decoded.
+type decodeState struct {
+	opts  DecoderOptions
+	depth int
+	terms int
+	path  []pathElem
+}
+
+// WithOptions returns a copy of b which enforces the given limits.
+func (b ErlExtBinary) WithOptions(opts DecoderOptions) ErlExtBinary {
+	b.state = &decodeState{opts: opts}
+	return b
+}
+
+func checkLimit(limit string, max int, value uint64) error {
+	if max > 0 && value > uint64(max) {
+		return &LimitError{Limit: limit, Max: max, Value: value}
+	}
+
+	return nil
+}
+
+// reset prepares the state for the next term.
+func (s *decodeState) reset() {
+	if s != nil {
+		s.depth, s.terms, s.path = 0, 0, s.path[:0]
+	}
+}
+
+// enter accounts for a term nested at the current depth, leave has to be
+// called once it has been decoded.
+func (s *decodeState) enter() error {
+	if s == nil {
+		return nil
+	}
+
+	s.depth++
+	if err := checkLimit("MaxDepth", s.opts.MaxDepth, uint64(s.depth)); err != nil {
+		return err
+	}
+
+	return s.addTerms(1)
+}
+
+func (s *decodeState) leave() {
+	if s != nil {
+		s.depth--
+	}
+}
+
+// addTerms accounts for n terms, which are not decoded on their own, like the
+// characters of STRING_EXT.
+func (s *decodeState) addTerms(n uint32) error {
+	if s == nil {
+		return nil
+	}
+
+	s.terms += int(n)
+	return checkLimit("MaxTerms", s.opts.MaxTerms, uint64(s.terms))
+}
+
+// checkElements verifies that n elements announced by a header fit into the
+// remaining number of terms, before room for them gets allocated.
+func (s *decodeState) checkElements(n uint64) error {
+	if s == nil {
+		return nil
+	}
+
+	return checkLimit("MaxTerms", s.opts.MaxTerms, uint64(s.terms)+n)
+}
+
+func (s *decodeState) checkBigIntBytes(n uint32) error {
+	if s == nil {
+		return nil
+	}
+
+	return checkLimit("MaxBigIntBytes", s.opts.MaxBigIntBytes, uint64(n))
+}
+
+func (s *decodeState) checkBinarySize(n uint32) error {
+	if s == nil {
+		return nil
+	}
+
+	return checkLimit("MaxBinarySize", s.opts.MaxBinarySize, uint64(n))
+}
+
+func (s *decodeState) checkDecompressedSize(n uint32) error {
+	if s == nil {
+		return nil
+	}
+
+	return checkLimit("MaxDecompressedSize", s.opts.MaxDecompressedSize, uint64(n))
+}
@@ -0,0 +1,72 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var limitsTestTable = []struct {
+	Name   string
+	Data   []byte
+	Opts   erlgo.DecoderOptions
+	Expect string
+}{
+	{"nested lists", []byte{131, 108, 0, 0, 0, 1, 108, 0, 0, 0, 1, 106, 106, 106}, erlgo.DecoderOptions{MaxDepth: 2}, "MaxDepth"},
+	{"nested tuples", []byte{131, 104, 1, 104, 1, 104, 0}, erlgo.DecoderOptions{MaxDepth: 2}, "MaxDepth"},
+	{"huge list header", []byte{131, 108, 255, 255, 255, 255}, erlgo.DecoderOptions{MaxTerms: 1000}, "MaxTerms"},
+	{"huge tuple header", []byte{131, 105, 255, 255, 255, 255}, erlgo.DecoderOptions{MaxTerms: 1000}, "MaxTerms"},
+	{"huge map header", []byte{131, 116, 127, 255, 255, 255}, erlgo.DecoderOptions{MaxTerms: 1000}, "MaxTerms"},
+	{"long string", []byte{131, 107, 0, 3, 1, 2, 3}, erlgo.DecoderOptions{MaxTerms: 3}, "MaxTerms"},
+	{"many small terms", []byte{131, 104, 3, 97, 1, 97, 2, 97, 3}, erlgo.DecoderOptions{MaxTerms: 3}, "MaxTerms"},
+	{"large big integer", []byte{131, 111, 255, 255, 255, 255, 0}, erlgo.DecoderOptions{MaxBigIntBytes: 64}, "MaxBigIntBytes"},
+	{"small big integer", []byte{131, 110, 9, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, erlgo.DecoderOptions{MaxBigIntBytes: 8}, "MaxBigIntBytes"},
+	{"binary", []byte{131, 109, 255, 255, 255, 255}, erlgo.DecoderOptions{MaxBinarySize: 1024}, "MaxBinarySize"},
+	{"bit binary", []byte{131, 77, 0, 0, 4, 1, 3}, erlgo.DecoderOptions{MaxBinarySize: 1024}, "MaxBinarySize"},
+	{"compressed", compressedData(1005, compressedBinary), erlgo.DecoderOptions{MaxDecompressedSize: 1000}, "MaxDecompressedSize"},
+	{"compressed binary", compressedData(1005, compressedBinary), erlgo.DecoderOptions{MaxBinarySize: 999}, "MaxBinarySize"},
+}
+
+func TestDecoderLimits(t *testing.T) {
+	for _, test := range limitsTestTable {
+		var limitErr *erlgo.LimitError
+		if val, err := erlgo.FromBytes(test.Data).WithOptions(test.Opts).Decode(); !errors.As(err, &limitErr) {
+			t.Errorf(`%v parsed into (%#v, %v), expected a *LimitError.`, test.Name, val, err)
+		} else if limitErr.Limit != test.Expect {
+			t.Errorf(`%v exceeded %v, expected %v.`, test.Name, limitErr.Limit, test.Expect)
+		}
+	}
+}
+
+func TestDecoderWithinLimits(t *testing.T) {
+	opts := erlgo.DecoderOptions{MaxDepth: 3, MaxTerms: 5, MaxBigIntBytes: 9, MaxBinarySize: 1000, MaxDecompressedSize: 1005}
+	for _, data := range [][]byte{
+		{131, 108, 0, 0, 0, 1, 108, 0, 0, 0, 1, 106, 106, 106},
+		{131, 104, 3, 97, 1, 97, 2, 97, 3},
+		{131, 107, 0, 3, 1, 2, 3},
+		{131, 110, 9, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		compressedData(1005, compressedBinary),
+	} {
+		if val, err := erlgo.FromBytes(data).WithOptions(opts).Decode(); err != nil {
+			t.Errorf(`%v encountered error "%v", expected to be within the limits.`, data, err)
+		} else if val == nil {
+			t.Errorf(`%v parsed into nil.`, data)
+		}
+	}
+}
+
+func TestDecoderOptionsPerTerm(t *testing.T) {
+	var buf bytes.Buffer
+	for i := 0; i < 10; i++ {
+		buf.Write([]byte{131, 104, 2, 97, 1, 97, 2})
+	}
+
+	d := erlgo.NewDecoder(&buf)
+	d.SetOptions(erlgo.DecoderOptions{MaxTerms: 3, MaxDepth: 2})
+	for i := 0; i < 10; i++ {
+		if _, err := d.Decode(); err != nil {
+			t.Fatalf(`term %v encountered error "%v", expected the limits to apply per term.`, i, err)
+		}
+	}
+}
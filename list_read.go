@@ -3,23 +3,31 @@ package erlgo
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 )
 
 type List interface {
-	ToSlice() ([]Term, err)
+	Term
+
+	ToSlice() ([]Term, error)
 	Len() int
+	IsProper() bool
 }
 
 type Nil struct{}
 
-func (n Nil) ToSlice() ([]Term, err) {
-	return []Term{}
+func (n Nil) ToSlice() ([]Term, error) {
+	return []Term{}, nil
 }
 
 func (n Nil) Len() int {
 	return 0
 }
 
+func (n Nil) IsProper() bool {
+	return true
+}
+
 func (n Nil) IsInteger() bool {
 	return false
 }
@@ -39,17 +47,68 @@ func (n Nil) Matches(other Term) bool {
 	}
 }
 
+// Cons is a single cell of a non-empty list. A list is proper when following
+// the tails eventually ends in Nil, otherwise it is improper, as in `[1|2]`.
 type Cons struct {
 	this Term
 	next Term
 }
 
-func (c Cons) ToSlice() ([]Term, err) {
-	return []Term{}
+func NewCons(head, tail Term) Cons {
+	return Cons{this: head, next: tail}
+}
+
+func (c Cons) Head() Term {
+	return c.this
+}
+
+func (c Cons) Tail() Term {
+	return c.next
+}
+
+// Elements walks the list and returns its elements together with the final
+// tail, which is Nil for proper lists.
+func (c Cons) Elements() ([]Term, Term) {
+	result := []Term{}
+
+	var current Term = c
+	for {
+		if cons, ok := current.(Cons); ok {
+			result = append(result, cons.this)
+			current = cons.next
+		} else {
+			return result, current
+		}
+	}
+}
+
+func (c Cons) ToSlice() ([]Term, error) {
+	if elements, tail := c.Elements(); !tail.Matches(Nil{}) {
+		return nil, errors.New("Not a proper list")
+	} else {
+		return elements, nil
+	}
 }
 
+// Len returns the number of cons cells, which for proper lists is the number
+// of elements. The tail of an improper list is not counted.
 func (c Cons) Len() int {
-	return 0
+	length := 0
+
+	var current Term = c
+	for {
+		if cons, ok := current.(Cons); ok {
+			length++
+			current = cons.next
+		} else {
+			return length
+		}
+	}
+}
+
+func (c Cons) IsProper() bool {
+	_, tail := c.Elements()
+	return tail.Matches(Nil{})
 }
 
 func (c Cons) IsInteger() bool {
@@ -63,20 +122,38 @@ func (c Cons) ToInteger() (Int, error) {
 }
 
 func (c Cons) Matches(other Term) bool {
-	switch o := other.(type) {
-	case Cons:
-		x, y := c, o
-
-		for x.IsList() && y.IsList() {
-
+	var x, y Term = c, other
+
+	for {
+		xc, xIsCons := x.(Cons)
+		yc, yIsCons := y.(Cons)
+
+		switch {
+		case xIsCons && yIsCons:
+			if !xc.this.Matches(yc.this) {
+				return false
+			}
+			x, y = xc.next, yc.next
+		case xIsCons || yIsCons:
+			return false
+		default:
+			return x.Matches(y)
 		}
-	default:
-		return false
 	}
 }
 
 func NewListFromTerms(terms []Term) List {
-	var result Cons = Nil{}
+	return newListWithTail(terms, Nil{})
+}
+
+func newListWithTail(terms []Term, tail Term) List {
+	if len(terms) == 0 {
+		if list, ok := tail.(List); ok {
+			return list
+		}
+	}
+
+	result := tail
 
 	for i := len(terms) - 1; i >= 0; i-- {
 		result = Cons{
@@ -85,12 +162,27 @@ func NewListFromTerms(terms []Term) List {
 		}
 	}
 
-	return result
+	return result.(Cons)
+}
+
+func decodeNil(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != nilExt {
+		return nil, fmt.Errorf("%v is not tagging nil", tag)
+	}
+
+	return Nil{}, nil
 }
 
 func decodeStringExt(b ErlExtBinary) (Term, error) {
-	_, err := b.bs.ReadByte()
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != stringExt {
+		return nil, fmt.Errorf("%v is not tagging a string", tag)
+	}
 
+	var err error
 	lengthBytes := []byte{0, 0}
 	if lengthBytes[0], err = b.bs.ReadByte(); err != nil {
 		return nil, err
@@ -99,6 +191,10 @@ func decodeStringExt(b ErlExtBinary) (Term, error) {
 	}
 
 	length := binary.BigEndian.Uint16(lengthBytes)
+	if err := b.state.addTerms(uint32(length)); err != nil {
+		return nil, err
+	}
+
 	result := make([]Term, length)
 
 	for i := uint16(0); i < length; i++ {
@@ -109,5 +205,45 @@ func decodeStringExt(b ErlExtBinary) (Term, error) {
 		}
 	}
 
-	return Cons{result, Nil{}}, nil
+	return NewListFromTerms(result), nil
+}
+
+func decodeList(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != listExt {
+		return nil, fmt.Errorf("%v is not tagging a list", tag)
+	}
+
+	length, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	} else if err := b.state.checkElements(uint64(uint32(length)) + 1); err != nil {
+		return nil, err
+	}
+
+	result := make([]Term, 0, initialCapacity(uint32(length)))
+
+	for i := uint32(0); i < uint32(length); i++ {
+		b.state.push("list", int(i), nil)
+		if elem, err := decodeRemaining(b); err != nil {
+			return nil, err
+		} else {
+			result = append(result, elem)
+		}
+		b.state.pop()
+	}
+
+	b.state.push("list.tail", -1, nil)
+	tail, err := decodeRemaining(b)
+	b.state.pop()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := tail.(List); len(result) == 0 && !ok {
+		return nil, fmt.Errorf("a list needs at least one element before its tail %v", tail)
+	}
+
+	return newListWithTail(result, tail), nil
 }
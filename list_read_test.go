@@ -5,6 +5,19 @@ import (
 	"testing"
 )
 
+func byteListData(length int, value byte) (erlgo.ErlExtBinary, erlgo.Term) {
+	data := []byte{131, 107, byte(length >> 8), byte(length)}
+	expect := make([]erlgo.Term, length)
+	for i := 0; i < length; i++ {
+		data = append(data, value)
+		expect[i] = erlgo.Int64(value)
+	}
+	return erlgo.FromBytes(data), erlgo.NewListFromTerms(expect)
+}
+
+var mediumByteList, mediumByteListExpect = byteListData(32000, 130)
+var longByteList, longByteListExpect = byteListData(65534, 130)
+
 var listTestTable = []struct {
 	Name   string
 	Data   erlgo.ErlExtBinary
@@ -12,8 +25,13 @@ var listTestTable = []struct {
 }{
 	{"empty list", erlgo.FromBytes([]byte{131, 106}), erlgo.NewListFromTerms([]erlgo.Term{})},
 	{"short byte list", erlgo.FromBytes([]byte{131, 107, 0, 1, 130}), erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(130)})},
-	{"medium byte list", erlgo.FromBytes([]byte{131, 107, 125, 0, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130}), erlgo.ProperList{}},
-	{"long byte list", erlgo.FromBytes([]byte{131, 107, 255, 254, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130}), erlgo.ProperList{}},
+	{"medium byte list", mediumByteList, mediumByteListExpect},
+	{"long byte list", longByteList, longByteListExpect},
+	{"[256, ok]", erlgo.FromBytes([]byte{131, 108, 0, 0, 0, 2, 98, 0, 0, 1, 0, 119, 2, 111, 107, 106}), erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(256), erlgo.Atom("ok")})},
+	{"[[], [[]]]", erlgo.FromBytes([]byte{131, 108, 0, 0, 0, 2, 106, 108, 0, 0, 0, 1, 106, 106, 106}), erlgo.NewListFromTerms([]erlgo.Term{erlgo.Nil{}, erlgo.NewListFromTerms([]erlgo.Term{erlgo.Nil{}})})},
+	{"[1|2]", erlgo.FromBytes([]byte{131, 108, 0, 0, 0, 1, 97, 1, 97, 2}), erlgo.NewCons(erlgo.Int64(1), erlgo.Int64(2))},
+	{"[1, 2|ok]", erlgo.FromBytes([]byte{131, 108, 0, 0, 0, 2, 97, 1, 97, 2, 119, 2, 111, 107}), erlgo.NewCons(erlgo.Int64(1), erlgo.NewCons(erlgo.Int64(2), erlgo.Atom("ok")))},
+	{"[1|\"ab\"]", erlgo.FromBytes([]byte{131, 108, 0, 0, 0, 1, 97, 1, 107, 0, 2, 97, 98}), erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(97), erlgo.Int64(98)})},
 }
 
 func TestReadingLists(t *testing.T) {
@@ -28,8 +46,58 @@ func TestReadingLists(t *testing.T) {
 	}
 }
 
+func TestListProperties(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		List   erlgo.List
+		Len    int
+		Proper bool
+	}{
+		{"[]", erlgo.Nil{}, 0, true},
+		{"[1, 2, 3]", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(2), erlgo.Int64(3)}), 3, true},
+		{"[1|2]", erlgo.NewCons(erlgo.Int64(1), erlgo.Int64(2)), 1, false},
+		{"[1, 2|3]", erlgo.NewCons(erlgo.Int64(1), erlgo.NewCons(erlgo.Int64(2), erlgo.Int64(3))), 2, false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.List.Len() != test.Len {
+				t.Errorf(`%#v.Len() returned %v, expected %v.`, test.List, test.List.Len(), test.Len)
+			}
+			if test.List.IsProper() != test.Proper {
+				t.Errorf(`%#v.IsProper() returned %v, expected %v.`, test.List, !test.Proper, test.Proper)
+			}
+			if slice, err := test.List.ToSlice(); test.Proper && (err != nil || len(slice) != test.Len) {
+				t.Errorf(`%#v.ToSlice() returned (%#v, %v), expected %v elements.`, test.List, slice, err, test.Len)
+			} else if !test.Proper && err == nil {
+				t.Errorf(`%#v.ToSlice() returned %#v, expected an error.`, test.List, slice)
+			}
+		})
+	}
+}
+
+func TestListMatches(t *testing.T) {
+	oneTwo := erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(2)})
+	for _, test := range []struct {
+		Name   string
+		Other  erlgo.Term
+		Expect bool
+	}{
+		{"same", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(2)}), true},
+		{"shorter", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1)}), false},
+		{"longer", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(2), erlgo.Int64(3)}), false},
+		{"improper", erlgo.NewCons(erlgo.Int64(1), erlgo.Int64(2)), false},
+		{"empty", erlgo.Nil{}, false},
+		{"not a list", erlgo.Tuple{erlgo.Int64(1), erlgo.Int64(2)}, false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if oneTwo.Matches(test.Other) != test.Expect {
+				t.Errorf(`%#v.Matches(%#v) returned %v, expected %v.`, oneTwo, test.Other, !test.Expect, test.Expect)
+			}
+		})
+	}
+}
+
 func BenchmarkReadingLists(b *testing.B) {
-	for _, data := range floatTestTable {
+	for _, data := range listTestTable {
 		b.Run(data.Name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				data.Data.Decode()
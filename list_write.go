@@ -0,0 +1,46 @@
+package erlgo
+
+import (
+	"bytes"
+	"math"
+)
+
+func encodeList(buf *bytes.Buffer, c Cons) error {
+	elements, tail := c.Elements()
+
+	if chars, ok := stringBytes(elements, tail); ok {
+		buf.WriteByte(stringExt)
+		writeUint16(buf, uint16(len(chars)))
+		buf.Write(chars)
+		return nil
+	}
+
+	buf.WriteByte(listExt)
+	writeUint32(buf, uint32(len(elements)))
+	for _, elem := range elements {
+		if err := encodeTerm(buf, elem); err != nil {
+			return err
+		}
+	}
+
+	return encodeTerm(buf, tail)
+}
+
+// stringBytes checks if a list qualifies for STRING_EXT, which is the case for
+// proper lists of at most 65535 integers between 0 and 255.
+func stringBytes(elements []Term, tail Term) ([]byte, bool) {
+	if _, ok := tail.(Nil); !ok || len(elements) > math.MaxUint16 {
+		return nil, false
+	}
+
+	result := make([]byte, len(elements))
+	for i, elem := range elements {
+		if c, ok := elem.(Int64); !ok || c < 0 || c > math.MaxUint8 {
+			return nil, false
+		} else {
+			result[i] = byte(c)
+		}
+	}
+
+	return result, true
+}
@@ -0,0 +1,55 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+func charListData(length int) (erlgo.Term, []byte) {
+	data := []byte{131, 108, byte(length >> 24), byte(length >> 16), byte(length >> 8), byte(length)}
+	terms := make([]erlgo.Term, length)
+	for i := range terms {
+		terms[i] = erlgo.Int64(0)
+		data = append(data, 97, 0)
+	}
+	return erlgo.NewListFromTerms(terms), append(data, 106)
+}
+
+// too long for STRING_EXT
+var longCharList, longCharListBytes = charListData(65536)
+
+var listWriteTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect []byte
+}{
+	{"[]", erlgo.Nil{}, []byte{131, 106}},
+	{"\"ab\"", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(97), erlgo.Int64(98)}), []byte{131, 107, 0, 2, 97, 98}},
+	{"[256]", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(256)}), []byte{131, 108, 0, 0, 0, 1, 98, 0, 0, 1, 0, 106}},
+	{"[1, ok]", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Atom("ok")}), []byte{131, 108, 0, 0, 0, 2, 97, 1, 119, 2, 111, 107, 106}},
+	{"[1|2]", erlgo.NewCons(erlgo.Int64(1), erlgo.Int64(2)), []byte{131, 108, 0, 0, 0, 1, 97, 1, 97, 2}},
+	{"65536 zeros", longCharList, longCharListBytes},
+}
+
+func TestWritingLists(t *testing.T) {
+	for _, test := range listWriteTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil && !bytes.Equal(data, test.Expect) {
+				t.Errorf(`%#v encoded into %v, expected %v.`, test.Term, data, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %v.`, test.Term, err, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkWritingLists(b *testing.B) {
+	for _, data := range listWriteTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Encode(data.Term)
+			}
+		})
+	}
+}
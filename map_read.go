@@ -0,0 +1,279 @@
+package erlgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+type Pair struct {
+	Key   Term
+	Value Term
+}
+
+// Map keeps its pairs in insertion order. Keys are compared using Matches, so
+// they can be arbitrary terms.
+type Map struct {
+	pairs []Pair
+
+	// keys holds the positions of the pairs by the hash of their keys, for
+	// maps which are not built by NewMap or a decoder it may be nil.
+	keys map[string][]int
+}
+
+// NewMap creates a map from the given pairs. Just as in Erlang a later pair
+// replaces an earlier one with the same key.
+func NewMap(pairs ...Pair) Map {
+	mb := newMapBuilder(len(pairs))
+	for _, p := range pairs {
+		mb.put(p.Key, p.Value)
+	}
+	return mb.result()
+}
+
+func (m Map) index(key Term) int {
+	if m.keys == nil {
+		for i, p := range m.pairs {
+			if p.Key.Matches(key) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, i := range m.keys[keyHash(key)] {
+		if m.pairs[i].Key.Matches(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mapBuilder collects the pairs of a map, which takes linear time unlike
+// calling Put for each of them.
+type mapBuilder struct {
+	pairs []Pair
+	keys  map[string][]int
+}
+
+func newMapBuilder(size int) *mapBuilder {
+	return &mapBuilder{pairs: make([]Pair, 0, size), keys: make(map[string][]int, size)}
+}
+
+// put adds the pair, or replaces the value of the pair with a matching key.
+// It reports whether the key is new.
+func (mb *mapBuilder) put(key, value Term) bool {
+	hash := keyHash(key)
+	for _, i := range mb.keys[hash] {
+		if mb.pairs[i].Key.Matches(key) {
+			mb.pairs[i].Value = value
+			return false
+		}
+	}
+
+	mb.keys[hash] = append(mb.keys[hash], len(mb.pairs))
+	mb.pairs = append(mb.pairs, Pair{Key: key, Value: value})
+	return true
+}
+
+func (mb *mapBuilder) has(key Term) bool {
+	for _, i := range mb.keys[keyHash(key)] {
+		if mb.pairs[i].Key.Matches(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (mb *mapBuilder) result() Map {
+	return Map{pairs: mb.pairs, keys: mb.keys}
+}
+
+// keyHash returns the same string for keys that match each other. Different
+// keys may share a hash, so they still have to be compared with Matches.
+func keyHash(key Term) string {
+	var buf bytes.Buffer
+	writeKeyHash(&buf, key)
+	return buf.String()
+}
+
+func writeKeyHash(buf *bytes.Buffer, key Term) {
+	switch k := key.(type) {
+	case Atom:
+		buf.WriteByte('a')
+		buf.WriteString(string(k))
+	case Int64:
+		buf.WriteByte('i')
+		buf.WriteString(strconv.FormatInt(int64(k), 10))
+	case IntBig:
+		buf.WriteByte('i')
+		buf.WriteString(k.Int.String())
+	case Float:
+		// 0.0 and -0.0 match each other
+		buf.WriteByte('f')
+		if k != 0 {
+			buf.WriteString(strconv.FormatFloat(float64(k), 'g', -1, 64))
+		}
+	case Binary:
+		buf.WriteByte('b')
+		buf.Write(k)
+	case BitString:
+		// only the significant bits count, whole bytes match a Binary
+		buf.WriteByte('b')
+		if len(k.Bytes) > 0 {
+			last := len(k.Bytes) - 1
+			buf.Write(k.Bytes[:last])
+			buf.WriteByte(k.Bytes[last] & byte(0xff<<(8-k.Bits)))
+			if k.Bits != 8 {
+				buf.WriteString(strconv.Itoa(int(k.Bits)))
+			}
+		}
+	case Tuple:
+		buf.WriteByte('{')
+		for _, elem := range k {
+			writeKeyHash(buf, elem)
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('}')
+	case Cons:
+		buf.WriteByte('[')
+		writeKeyHash(buf, k.this)
+		buf.WriteByte('|')
+		writeKeyHash(buf, k.next)
+	case Pid, Port, Reference, Export:
+		fmt.Fprintf(buf, "%T%v", k, k)
+	default:
+		// like maps, whose pairs may come in any order, or funs
+		fmt.Fprintf(buf, "%T", k)
+	}
+}
+
+func (m Map) Len() int {
+	return len(m.pairs)
+}
+
+func (m Map) Get(key Term) (Term, bool) {
+	if i := m.index(key); i >= 0 {
+		return m.pairs[i].Value, true
+	}
+	return nil, false
+}
+
+// Put returns a new map with key associated to value, the receiver stays
+// untouched. It copies the map, so NewMap is the way to build a large one.
+func (m Map) Put(key, value Term) Map {
+	pairs := make([]Pair, len(m.pairs), len(m.pairs)+1)
+	copy(pairs, m.pairs)
+
+	if i := m.index(key); i >= 0 {
+		pairs[i].Value = value
+		return Map{pairs: pairs, keys: m.keys}
+	}
+	pairs = append(pairs, Pair{Key: key, Value: value})
+
+	if m.keys == nil {
+		return Map{pairs: pairs}
+	}
+	keys := make(map[string][]int, len(m.keys)+1)
+	for hash, indexes := range m.keys {
+		keys[hash] = indexes
+	}
+	hash := keyHash(key)
+	keys[hash] = append(keys[hash][:len(keys[hash]):len(keys[hash])], len(pairs)-1)
+
+	return Map{pairs: pairs, keys: keys}
+}
+
+// Pairs returns the pairs in insertion order, which for decoded maps is the
+// order they had on the wire.
+func (m Map) Pairs() []Pair {
+	pairs := make([]Pair, len(m.pairs))
+	copy(pairs, m.pairs)
+	return pairs
+}
+
+// SortedPairs returns the pairs ordered by their keys in Erlang term order.
+func (m Map) SortedPairs() []Pair {
+	pairs := m.Pairs()
+	sort.Stable(byKey(pairs))
+	return pairs
+}
+
+// byKey sorts pairs by their keys in Erlang term order.
+type byKey []Pair
+
+func (p byKey) Len() int           { return len(p) }
+func (p byKey) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byKey) Less(i, j int) bool { return compareTerms(p[i].Key, p[j].Key, true) < 0 }
+
+func (m Map) IsInteger() bool {
+	return false
+}
+
+func (m Map) IsList() bool { return false }
+
+func (m Map) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+func (m Map) Matches(other Term) bool {
+	switch o := other.(type) {
+	case Map:
+		if m.Len() != o.Len() {
+			return false
+		}
+		for _, p := range m.pairs {
+			if v, ok := o.Get(p.Key); !ok || !p.Value.Matches(v) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func decodeMap(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != mapExt {
+		return nil, fmt.Errorf("%v is not tagging a map", tag)
+	}
+
+	arity, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	} else if err := b.state.checkElements(2 * uint64(uint32(arity))); err != nil {
+		return nil, err
+	}
+
+	mb := newMapBuilder(initialCapacity(uint32(arity)))
+
+	for i := uint32(0); i < uint32(arity); i++ {
+		b.state.push("map.key", int(i), nil)
+		offset, tag := b.offset(), uint8(0)
+		if next, err := b.bs.Peek(1); err == nil {
+			tag = next[0]
+		}
+		key, err := decodeRemaining(b)
+		if err != nil {
+			return nil, err
+		} else if mb.has(key) {
+			return nil, b.syntaxError(offset, tag, ErrDuplicateKey)
+		}
+		b.state.pop()
+
+		b.state.push("map", -1, key)
+		value, err := decodeRemaining(b)
+		if err != nil {
+			return nil, err
+		}
+		b.state.pop()
+
+		mb.put(key, value)
+	}
+
+	return mb.result(), nil
+}
@@ -0,0 +1,188 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"math"
+	"math/big"
+	"testing"
+)
+
+var mapTestTable = []struct {
+	Name   string
+	Data   erlgo.ErlExtBinary
+	Expect erlgo.Term
+}{
+	{"#{}", erlgo.FromBytes([]byte{131, 116, 0, 0, 0, 0}), erlgo.NewMap()},
+	{"#{a => 1, b => #{}}", erlgo.FromBytes([]byte{131, 116, 0, 0, 0, 2, 119, 1, 97, 97, 1, 119, 1, 98, 116, 0, 0, 0, 0}), erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)},
+		erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.NewMap()},
+	)},
+	{"#{{1, 2} => ok}", erlgo.FromBytes([]byte{131, 116, 0, 0, 0, 1, 104, 2, 97, 1, 97, 2, 119, 2, 111, 107}), erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Tuple{erlgo.Int64(1), erlgo.Int64(2)}, Value: erlgo.Atom("ok")},
+	)},
+	{"%Foo{x: 1}", erlgo.FromBytes([]byte{131, 116, 0, 0, 0, 2, 119, 10, 95, 95, 115, 116, 114, 117, 99, 116, 95, 95, 119, 10, 69, 108, 105, 120, 105, 114, 46, 70, 111, 111, 119, 1, 120, 97, 1}), erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Atom("x"), Value: erlgo.Int64(1)},
+		erlgo.Pair{Key: erlgo.Atom("__struct__"), Value: erlgo.Atom("Elixir.Foo")},
+	)},
+}
+
+func TestReadingMaps(t *testing.T) {
+	for _, test := range mapTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if val, err := test.Data.Decode(); err == nil && !val.Matches(test.Expect) {
+				t.Errorf(`%#v parsed into %#v, expected %#v."`, test.Data, val, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected value %#v.`, test.Data, err, test.Expect)
+			}
+		})
+	}
+}
+
+func TestMapLookup(t *testing.T) {
+	m := erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)},
+		erlgo.Pair{Key: erlgo.Binary("a"), Value: erlgo.Int64(2)},
+		erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(3)},
+	)
+
+	if m.Len() != 2 {
+		t.Errorf(`%#v.Len() returned %v, expected 2.`, m, m.Len())
+	}
+	if val, ok := m.Get(erlgo.Atom("a")); !ok || !val.Matches(erlgo.Int64(3)) {
+		t.Errorf(`%#v.Get(a) returned (%#v, %v), expected 3.`, m, val, ok)
+	}
+	if val, ok := m.Get(erlgo.Binary("a")); !ok || !val.Matches(erlgo.Int64(2)) {
+		t.Errorf(`%#v.Get(<<"a">>) returned (%#v, %v), expected 2.`, m, val, ok)
+	}
+	if val, ok := m.Get(erlgo.Atom("b")); ok {
+		t.Errorf(`%#v.Get(b) returned %#v, expected no value.`, m, val)
+	}
+
+	updated := m.Put(erlgo.Atom("b"), erlgo.Int64(4))
+	if _, ok := m.Get(erlgo.Atom("b")); ok {
+		t.Errorf(`Put modified the original map %#v.`, m)
+	}
+	if val, ok := updated.Get(erlgo.Atom("b")); !ok || !val.Matches(erlgo.Int64(4)) {
+		t.Errorf(`%#v.Get(b) returned (%#v, %v), expected 4.`, updated, val, ok)
+	}
+}
+
+func TestMapMatchingKeys(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		A, B erlgo.Term
+	}{
+		{"small and big integer", erlgo.Int64(1), erlgo.IntBig{big.NewInt(1)}},
+		{"binary and bitstring", erlgo.Binary("a"), erlgo.BitString{Bytes: []byte("a"), Bits: 8}},
+		{"unused bits", erlgo.BitString{Bytes: []byte{0xa0}, Bits: 3}, erlgo.BitString{Bytes: []byte{0xbf}, Bits: 3}},
+		{"signed zeros", erlgo.Float(0), erlgo.Float(math.Copysign(0, -1))},
+		{"lists", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1)}), erlgo.NewCons(erlgo.IntBig{big.NewInt(1)}, erlgo.Nil{})},
+		{"maps", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Nil{}}, erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.Nil{}}),
+			erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.Nil{}}, erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Nil{}})},
+	} {
+		m := erlgo.NewMap(erlgo.Pair{Key: test.A, Value: erlgo.Int64(1)}, erlgo.Pair{Key: test.B, Value: erlgo.Int64(2)})
+		if m.Len() != 1 {
+			t.Errorf(`%v: map has %v keys, expected 1.`, test.Name, m.Len())
+		}
+		if val, ok := m.Get(test.A); !ok || !val.Matches(erlgo.Int64(2)) {
+			t.Errorf(`%v: Get returned (%#v, %v), expected 2.`, test.Name, val, ok)
+		}
+	}
+}
+
+func TestLargeMap(t *testing.T) {
+	pairs := make([]erlgo.Pair, 100000)
+	for i := range pairs {
+		pairs[i] = erlgo.Pair{Key: erlgo.Tuple{erlgo.Int64(i)}, Value: erlgo.Int64(i)}
+	}
+
+	m := erlgo.NewMap(pairs...).Put(erlgo.Atom("new"), erlgo.Nil{})
+	if m.Len() != len(pairs)+1 {
+		t.Errorf(`map has %v keys, expected %v.`, m.Len(), len(pairs)+1)
+	}
+	if val, ok := m.Get(erlgo.Tuple{erlgo.Int64(4711)}); !ok || !val.Matches(erlgo.Int64(4711)) {
+		t.Errorf(`Get({4711}) returned (%#v, %v), expected 4711.`, val, ok)
+	}
+	if _, ok := m.Get(erlgo.Atom("new")); !ok {
+		t.Errorf(`Get(new) found no value after Put.`)
+	}
+}
+
+func TestMapOrdering(t *testing.T) {
+	m := erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Binary("b"), Value: erlgo.Int64(0)},
+		erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.Int64(1)},
+		erlgo.Pair{Key: erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1)}), Value: erlgo.Int64(2)},
+		erlgo.Pair{Key: erlgo.Float(1.0), Value: erlgo.Int64(3)},
+		erlgo.Pair{Key: erlgo.Tuple{erlgo.Int64(1)}, Value: erlgo.Int64(4)},
+		erlgo.Pair{Key: erlgo.Nil{}, Value: erlgo.Int64(5)},
+		erlgo.Pair{Key: erlgo.Int64(1), Value: erlgo.Int64(6)},
+		erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(7)},
+		erlgo.Pair{Key: erlgo.NewMap(), Value: erlgo.Int64(8)},
+	)
+
+	inserted := []int64{0, 1, 2, 3, 4, 5, 6, 7, 8}
+	for i, p := range m.Pairs() {
+		if !p.Value.Matches(erlgo.Int64(inserted[i])) {
+			t.Errorf(`pair %v in insertion order is %#v, expected value %v.`, i, p, inserted[i])
+		}
+	}
+
+	sorted := []int64{6, 3, 7, 1, 4, 8, 5, 2, 0}
+	for i, p := range m.SortedPairs() {
+		if !p.Value.Matches(erlgo.Int64(sorted[i])) {
+			t.Errorf(`pair %v in term order is %#v, expected value %v.`, i, p, sorted[i])
+		}
+	}
+}
+
+func TestMapSortedPairsNumbers(t *testing.T) {
+	m := erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Float(1.0), Value: erlgo.Atom("a")},
+		erlgo.Pair{Key: erlgo.Int64(2), Value: erlgo.Atom("b")},
+		erlgo.Pair{Key: erlgo.Float(0.5), Value: erlgo.Atom("c")},
+		erlgo.Pair{Key: erlgo.Int64(3), Value: erlgo.Atom("d")},
+	)
+
+	// all integers sort before all floats
+	sorted := []erlgo.Atom{"b", "d", "c", "a"}
+	for i, p := range m.SortedPairs() {
+		if !p.Value.Matches(sorted[i]) {
+			t.Errorf(`pair %v in term order is %#v, expected value %v.`, i, p, sorted[i])
+		}
+	}
+}
+
+func TestMapMatches(t *testing.T) {
+	ab := erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)},
+		erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.Int64(2)},
+	)
+	for _, test := range []struct {
+		Name   string
+		Other  erlgo.Term
+		Expect bool
+	}{
+		{"same", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)}, erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.Int64(2)}), true},
+		{"different order", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.Int64(2)}, erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)}), true},
+		{"different value", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)}, erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.Int64(3)}), false},
+		{"subset", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)}), false},
+		{"not a map", erlgo.Nil{}, false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if ab.Matches(test.Other) != test.Expect {
+				t.Errorf(`%#v.Matches(%#v) returned %v, expected %v.`, ab, test.Other, !test.Expect, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkReadingMaps(b *testing.B) {
+	for _, data := range mapTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				data.Data.Decode()
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package erlgo
+
+import "bytes"
+
+func encodeMap(buf *bytes.Buffer, m Map) error {
+	buf.WriteByte(mapExt)
+	writeUint32(buf, uint32(len(m.pairs)))
+
+	for _, p := range m.pairs {
+		if err := encodeTerm(buf, p.Key); err != nil {
+			return err
+		}
+		if err := encodeTerm(buf, p.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
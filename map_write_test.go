@@ -0,0 +1,38 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var mapWriteTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect []byte
+}{
+	{"#{}", erlgo.NewMap(), []byte{131, 116, 0, 0, 0, 0}},
+	{"#{b => 1, a => <<>>}", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.Int64(1)}, erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Binary{}}), []byte{131, 116, 0, 0, 0, 2, 119, 1, 98, 97, 1, 119, 1, 97, 109, 0, 0, 0, 0}},
+}
+
+func TestWritingMaps(t *testing.T) {
+	for _, test := range mapWriteTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil && !bytes.Equal(data, test.Expect) {
+				t.Errorf(`%#v encoded into %v, expected %v.`, test.Term, data, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %v.`, test.Term, err, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkWritingMaps(b *testing.B) {
+	for _, data := range mapWriteTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Encode(data.Term)
+			}
+		})
+	}
+}
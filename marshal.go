@@ -0,0 +1,277 @@
+package erlgo
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Marshaler is implemented by types that provide their own term
+// representation.
+type Marshaler interface {
+	MarshalTerm() (Term, error)
+}
+
+// Record marks a struct to be marshalled into an Erlang record, a tuple tagged
+// with the record name followed by the fields in declaration order. The name
+// is taken from the `erl` tag of the embedded Record, or is the lower cased
+// name of the struct otherwise.
+//
+//	type person struct {
+//		erlgo.Record `erl:"person"`
+//		Name string
+//		Age  int
+//	}
+type Record struct{}
+
+// An UnsupportedTypeError is returned by Marshal when a value of an
+// unsupported type is encountered.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("erlgo: unsupported type: %v", e.Type)
+}
+
+var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+var termType = reflect.TypeOf((*Term)(nil)).Elem()
+var recordType = reflect.TypeOf(Record{})
+
+// Marshal returns the external term format encoding of v.
+//
+// Bools become the atoms `true` and `false`, integers and big.Int values
+// become integers, floats become floats, strings and byte slices become
+// binaries, other slices and arrays become lists and Go maps become maps. A
+// nil pointer, interface or map becomes the atom `undefined`. Structs become
+// maps with atom keys named after their fields, or records if they embed
+// Record. Values implementing Term are used as they are, while values
+// implementing Marshaler provide their own term.
+//
+// The `erl` struct tag gives the name of a field, followed by comma separated
+// options:
+//
+//	atom       encode strings as atoms
+//	charlist   encode strings as lists of code points
+//	tuple      encode slices and arrays as tuples
+//	nil        encode nil as the atom `nil`, as Elixir does
+//	omitempty  leave out zero values from maps
+//
+// A field tagged with `erl:"-"` is ignored.
+func Marshal(v interface{}) ([]byte, error) {
+	term, err := MarshalTerm(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return Encode(term)
+}
+
+// MarshalTerm converts v into a term, following the same rules as Marshal.
+func MarshalTerm(v interface{}) (Term, error) {
+	return marshalValue(reflect.ValueOf(v), field{})
+}
+
+func marshalValue(v reflect.Value, f field) (Term, error) {
+	if !v.IsValid() {
+		return nilAtom(f), nil
+	}
+
+	if v.Type().Implements(marshalerType) {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return nilAtom(f), nil
+		}
+		return v.Interface().(Marshaler).MarshalTerm()
+	} else if v.CanAddr() && v.Addr().Type().Implements(marshalerType) {
+		return v.Addr().Interface().(Marshaler).MarshalTerm()
+	}
+
+	if v.Kind() != reflect.Ptr && v.Type().Implements(termType) {
+		if v.Kind() == reflect.Interface && v.IsNil() {
+			return nilAtom(f), nil
+		}
+		return v.Interface().(Term), nil
+	}
+
+	switch v.Type() {
+	case bigIntType:
+		i := v.Interface().(big.Int)
+		return IntBig{&i}, nil
+	case reflect.PtrTo(bigIntType):
+		if v.IsNil() {
+			return nilAtom(f), nil
+		}
+		return IntBig{new(big.Int).Set(v.Interface().(*big.Int))}, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nilAtom(f), nil
+		}
+		return marshalValue(v.Elem(), f)
+	case reflect.Bool:
+		if v.Bool() {
+			return True, nil
+		}
+		return False, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Int64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := v.Uint(); n > math.MaxInt64 {
+			return IntBig{new(big.Int).SetUint64(n)}, nil
+		} else {
+			return Int64(n), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		return Float(v.Float()), nil
+	case reflect.String:
+		return marshalString(v.String(), f), nil
+	case reflect.Slice:
+		if v.IsNil() && f.hasOption("tuple") {
+			return Tuple{}, nil
+		} else if v.Type().Elem().Kind() == reflect.Uint8 && !f.hasOption("tuple") {
+			return Binary(append([]byte{}, v.Bytes()...)), nil
+		}
+		return marshalSequence(v, f)
+	case reflect.Array:
+		return marshalSequence(v, f)
+	case reflect.Map:
+		if v.IsNil() {
+			return nilAtom(f), nil
+		}
+		return marshalMap(v)
+	case reflect.Struct:
+		return marshalStruct(v)
+	}
+
+	return nil, &UnsupportedTypeError{v.Type()}
+}
+
+func nilAtom(f field) Atom {
+	if f.hasOption("nil") {
+		return Atom("nil")
+	}
+	return Atom("undefined")
+}
+
+func marshalString(s string, f field) Term {
+	switch {
+	case f.hasOption("atom"):
+		return Atom(s)
+	case f.hasOption("charlist"):
+		runes := []rune(s)
+		chars := make([]Term, len(runes))
+		for i, r := range runes {
+			chars[i] = Int64(r)
+		}
+		return NewListFromTerms(chars)
+	default:
+		return Binary(s)
+	}
+}
+
+func marshalSequence(v reflect.Value, f field) (Term, error) {
+	elements := make([]Term, v.Len())
+	for i := range elements {
+		elem, err := marshalValue(v.Index(i), f)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = elem
+	}
+
+	if f.hasOption("tuple") {
+		return Tuple(elements), nil
+	}
+	return NewListFromTerms(elements), nil
+}
+
+// marshalMap orders the pairs by their keys, as Go does not give any
+// guarantees about the order of map iteration.
+func marshalMap(v reflect.Value) (Term, error) {
+	pairs := make([]Pair, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		k, err := marshalValue(key, field{})
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := marshalValue(v.MapIndex(key), field{})
+		if err != nil {
+			return nil, err
+		}
+
+		pairs = append(pairs, Pair{Key: k, Value: value})
+	}
+
+	sort.Sort(byKey(pairs))
+
+	return Map{pairs: pairs}, nil
+}
+
+func marshalStruct(v reflect.Value) (Term, error) {
+	fields := structFields(v.Type())
+
+	if name, ok := recordName(v.Type()); ok {
+		result := Tuple{name}
+		for _, f := range fields {
+			value, err := marshalValue(v.FieldByIndex(f.index), f)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		return result, nil
+	}
+
+	pairs := make([]Pair, 0, len(fields))
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.index)
+		if f.hasOption("omitempty") && isEmptyValue(fv) {
+			continue
+		}
+
+		value, err := marshalValue(fv, f)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, Pair{Key: Atom(f.name), Value: value})
+	}
+
+	return Map{pairs: pairs}, nil
+}
+
+// recordName returns the name of the record if t embeds Record.
+func recordName(t reflect.Type) (Atom, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if sf := t.Field(i); sf.Anonymous && sf.Type == recordType {
+			if name := strings.Split(sf.Tag.Get("erl"), ",")[0]; name != "" {
+				return Atom(name), true
+			}
+			return Atom(strings.ToLower(t.Name())), true
+		}
+	}
+	return "", false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package erlgo_test
+
+import (
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"math/big"
+	"testing"
+)
+
+type personRecord struct {
+	erlgo.Record `erl:"person"`
+	Name         string
+	Age          int
+}
+
+type Account struct {
+	erlgo.Record
+	ID int
+}
+
+type options struct {
+	Name    string   `erl:"name,atom"`
+	Label   string   `erl:"label,charlist"`
+	Tags    []string `erl:"tags,atom"`
+	Pair    []int    `erl:"pair,tuple"`
+	Parent  *options `erl:"parent,nil"`
+	Comment string   `erl:"comment,omitempty"`
+	Skipped int      `erl:"-"`
+	hidden  int
+}
+
+type celsius float64
+
+func (c celsius) MarshalTerm() (erlgo.Term, error) {
+	return erlgo.Tuple{erlgo.Atom("celsius"), erlgo.Float(c)}, nil
+}
+
+type withMarshaler struct {
+	Temperature erlgo.Marshaler `erl:"temperature"`
+}
+
+type failing struct{}
+
+func (failing) MarshalTerm() (erlgo.Term, error) {
+	return nil, errors.New("failing")
+}
+
+var marshalTestTable = []struct {
+	Name   string
+	Value  interface{}
+	Expect erlgo.Term
+}{
+	{"nil", nil, erlgo.Atom("undefined")},
+	{"true", true, erlgo.True},
+	{"int", -5, erlgo.Int64(-5)},
+	{"uint64", uint64(1<<64 - 1), erlgo.IntBig{new(big.Int).SetUint64(1<<64 - 1)}},
+	{"*big.Int", plusNine, erlgo.IntBig{plusNine}},
+	{"big.Int", *big.NewInt(3), erlgo.Int64(3)},
+	{"float", 1.5, erlgo.Float(1.5)},
+	{"string", "héllo", erlgo.Binary("héllo")},
+	{"[]byte", []byte{1, 2}, erlgo.Binary{1, 2}},
+	{"[]int", []int{1, 2}, erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(2)})},
+	{"empty []int", []int{}, erlgo.Nil{}},
+	{"[2]bool", [2]bool{true, false}, erlgo.NewListFromTerms([]erlgo.Term{erlgo.True, erlgo.False})},
+	{"map", map[string]int{"b": 2, "a": 1}, erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Binary("a"), Value: erlgo.Int64(1)},
+		erlgo.Pair{Key: erlgo.Binary("b"), Value: erlgo.Int64(2)},
+	)},
+	{"nil map", map[string]int(nil), erlgo.Atom("undefined")},
+	{"nil pointer", (*int)(nil), erlgo.Atom("undefined")},
+	{"term", erlgo.Tuple{erlgo.Atom("ok")}, erlgo.Tuple{erlgo.Atom("ok")}},
+	{"marshaler", celsius(21.5), erlgo.Tuple{erlgo.Atom("celsius"), erlgo.Float(21.5)}},
+	{"nil marshaler", withMarshaler{}, erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("temperature"), Value: erlgo.Atom("undefined")})},
+	{"marshaler field", withMarshaler{celsius(3)}, erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Atom("temperature"), Value: erlgo.Tuple{erlgo.Atom("celsius"), erlgo.Float(3)}})},
+	{"record", personRecord{Name: "Joe", Age: 42}, erlgo.Tuple{erlgo.Atom("person"), erlgo.Binary("Joe"), erlgo.Int64(42)}},
+	{"record named after struct", Account{ID: 1}, erlgo.Tuple{erlgo.Atom("account"), erlgo.Int64(1)}},
+	{"struct with options", &options{Name: "n", Label: "hé", Tags: []string{"a"}, Pair: []int{1, 2}, Skipped: 1, hidden: 1}, erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Atom("name"), Value: erlgo.Atom("n")},
+		erlgo.Pair{Key: erlgo.Atom("label"), Value: erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(104), erlgo.Int64(233)})},
+		erlgo.Pair{Key: erlgo.Atom("tags"), Value: erlgo.NewListFromTerms([]erlgo.Term{erlgo.Atom("a")})},
+		erlgo.Pair{Key: erlgo.Atom("pair"), Value: erlgo.Tuple{erlgo.Int64(1), erlgo.Int64(2)}},
+		erlgo.Pair{Key: erlgo.Atom("parent"), Value: erlgo.Atom("nil")},
+	)},
+}
+
+func TestMarshal(t *testing.T) {
+	for _, test := range marshalTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			data, err := erlgo.Marshal(test.Value)
+			if err != nil {
+				t.Fatalf(`%#v encountered error "%v", expected %#v.`, test.Value, err, test.Expect)
+			}
+
+			if val, err := erlgo.FromBytes(data).Decode(); err != nil {
+				t.Errorf(`%v encountered error "%v", expected %#v.`, data, err, test.Expect)
+			} else if !val.Matches(test.Expect) {
+				t.Errorf(`%#v marshalled into %#v, expected %#v.`, test.Value, val, test.Expect)
+			}
+		})
+	}
+}
+
+func TestMarshalSortsMapKeys(t *testing.T) {
+	expect := []byte{131, 116, 0, 0, 0, 3, 97, 1, 109, 0, 0, 0, 0, 119, 1, 97, 109, 0, 0, 0, 0, 119, 1, 98, 109, 0, 0, 0, 0}
+	for i := 0; i < 10; i++ {
+		data, err := erlgo.Marshal(map[interface{}]string{erlgo.Atom("b"): "", erlgo.Atom("a"): "", 1: ""})
+		if err != nil {
+			t.Fatalf(`encountered error "%v".`, err)
+		} else if string(data) != string(expect) {
+			t.Fatalf(`map marshalled into %v, expected %v.`, data, expect)
+		}
+	}
+}
+
+func TestMarshalRecordRoundTrip(t *testing.T) {
+	data, err := erlgo.Marshal(personRecord{Name: "Joe", Age: 42})
+	if err != nil {
+		t.Fatalf(`encountered error "%v".`, err)
+	}
+
+	var p personRecord
+	if err := erlgo.Unmarshal(data, &p); err != nil {
+		t.Errorf(`encountered error "%v".`, err)
+	} else if p.Name != "Joe" || p.Age != 42 {
+		t.Errorf(`unmarshalled into %#v.`, p)
+	}
+
+	var a Account
+	if err := erlgo.Unmarshal(data, &a); err == nil {
+		t.Errorf(`person record unmarshalled into %#v, expected an error.`, a)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	if _, err := erlgo.Marshal(make(chan int)); err == nil {
+		t.Errorf(`marshalling a channel succeeded, expected an error.`)
+	} else if _, ok := err.(*erlgo.UnsupportedTypeError); !ok {
+		t.Errorf(`marshalling a channel returned %#v, expected an UnsupportedTypeError.`, err)
+	}
+
+	if _, err := erlgo.Marshal([]interface{}{failing{}}); err == nil || err.Error() != "failing" {
+		t.Errorf(`marshalling a failing Marshaler returned %v, expected its error.`, err)
+	}
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	for _, data := range marshalTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Marshal(data.Value)
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package erlgo
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+)
+
+// Ranks of the types in Erlang's term order:
+// number < atom < reference < fun < port < pid < tuple < map < nil < list < bitstring
+const (
+	rankNumber = iota
+	rankAtom
+	rankReference
+	rankFun
+	rankPort
+	rankPid
+	rankTuple
+	rankMap
+	rankNil
+	rankList
+	rankBitString
+	rankUnknown
+)
+
+func typeRank(t Term) int {
+	switch t.(type) {
+	case Int64, IntBig, Float:
+		return rankNumber
+	case Atom:
+		return rankAtom
+	case Reference:
+		return rankReference
+	case Fun, Export:
+		return rankFun
+	case Port:
+		return rankPort
+	case Pid:
+		return rankPid
+	case Tuple:
+		return rankTuple
+	case Map:
+		return rankMap
+	case Nil:
+		return rankNil
+	case Cons:
+		return rankList
+	case Binary, BitString:
+		return rankBitString
+	default:
+		return rankUnknown
+	}
+}
+
+// Compare orders a and b the way Erlang's comparison operators do, and
+// returns -1, 0 or 1. Integers and floats compare by their value, so 1 and 1.0
+// are equal. NaN, which Erlang does not have, sorts after all other numbers.
+func Compare(a, b Term) int {
+	return compareTerms(a, b, false)
+}
+
+// CompareExact is the total order Erlang uses for map keys, which is Compare
+// except that all integers sort before all floats, so 2 is less than 1.0.
+func CompareExact(a, b Term) int {
+	return compareTerms(a, b, true)
+}
+
+// Equal is Erlang's `==`.
+func Equal(a, b Term) bool {
+	return Compare(a, b) == 0
+}
+
+// ExactEqual is Erlang's `=:=`.
+func ExactEqual(a, b Term) bool {
+	return CompareExact(a, b) == 0
+}
+
+// compareTerms orders a and b the way Erlang does and returns -1, 0 or 1.
+// If exact is set integers and floats are never equal, but all integers sort
+// before all floats, as they do for map keys.
+func compareTerms(a, b Term, exact bool) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		return compareInts(ra, rb)
+	}
+
+	switch ra {
+	case rankNumber:
+		return compareNumbers(a, b, exact)
+	case rankAtom:
+		return compareStrings(string(a.(Atom)), string(b.(Atom)))
+	case rankReference:
+		return compareReferences(a.(Reference), b.(Reference))
+	case rankFun:
+		return compareFuns(a, b, exact)
+	case rankPort:
+		pa, pb := a.(Port), b.(Port)
+		return compareIdentifiers(pa.Node, pb.Node, []uint64{pa.ID, uint64(pa.Creation)}, []uint64{pb.ID, uint64(pb.Creation)})
+	case rankPid:
+		pa, pb := a.(Pid), b.(Pid)
+		return compareIdentifiers(pa.Node, pb.Node,
+			[]uint64{uint64(pa.Serial), uint64(pa.ID), uint64(pa.Creation)},
+			[]uint64{uint64(pb.Serial), uint64(pb.ID), uint64(pb.Creation)})
+	case rankTuple:
+		ta, tb := a.(Tuple), b.(Tuple)
+		if len(ta) != len(tb) {
+			return compareInts(len(ta), len(tb))
+		}
+		for i := range ta {
+			if c := compareTerms(ta[i], tb[i], exact); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case rankMap:
+		return compareMaps(a.(Map), b.(Map), exact)
+	case rankNil:
+		return 0
+	case rankList:
+		return compareLists(a.(Cons), b.(Cons), exact)
+	case rankBitString:
+		return compareBitStrings(toBitString(a), toBitString(b))
+	default:
+		return 0
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareNumbers(a, b Term, exact bool) int {
+	fa, aIsFloat := a.(Float)
+	fb, bIsFloat := b.(Float)
+
+	aIsNaN, bIsNaN := aIsFloat && math.IsNaN(float64(fa)), bIsFloat && math.IsNaN(float64(fb))
+	switch {
+	case aIsNaN && bIsNaN:
+		return 0
+	case aIsNaN:
+		return 1
+	case bIsNaN:
+		return -1
+	}
+
+	switch {
+	case aIsFloat && bIsFloat:
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	case !aIsFloat && !bIsFloat:
+		ia, ib := a.(Int), b.(Int)
+		if va, ok := ia.Int64(); ok {
+			if vb, ok := ib.Int64(); ok {
+				switch {
+				case va < vb:
+					return -1
+				case va > vb:
+					return 1
+				default:
+					return 0
+				}
+			}
+		}
+		return ia.BigInt().Cmp(ib.BigInt())
+	}
+
+	// in the exact order of map keys all integers sort before all floats
+	if exact {
+		if aIsFloat {
+			return 1
+		}
+		return -1
+	}
+
+	var c int
+	if aIsFloat {
+		c = big.NewFloat(float64(fa)).Cmp(new(big.Float).SetInt(b.(Int).BigInt()))
+	} else {
+		c = new(big.Float).SetInt(a.(Int).BigInt()).Cmp(big.NewFloat(float64(fb)))
+	}
+	return c
+}
+
+// compareFuns orders funs by module first, local funs before exports, and
+// then by their remaining fields.
+func compareFuns(a, b Term, exact bool) int {
+	moduleOf := func(t Term) Atom {
+		if f, ok := t.(Fun); ok {
+			return f.Module
+		}
+		return t.(Export).Module
+	}
+
+	if c := compareStrings(string(moduleOf(a)), string(moduleOf(b))); c != 0 {
+		return c
+	}
+
+	fa, aIsFun := a.(Fun)
+	fb, bIsFun := b.(Fun)
+	switch {
+	case aIsFun && !bIsFun:
+		return -1
+	case !aIsFun && bIsFun:
+		return 1
+	case !aIsFun && !bIsFun:
+		ea, eb := a.(Export), b.(Export)
+		if c := compareStrings(string(ea.Function), string(eb.Function)); c != 0 {
+			return c
+		}
+		return compareInts(int(ea.Arity), int(eb.Arity))
+	}
+
+	if c := compareInts(int(fa.OldIndex), int(fb.OldIndex)); c != 0 {
+		return c
+	}
+	if c := compareInts(int(fa.OldUniq), int(fb.OldUniq)); c != 0 {
+		return c
+	}
+	if c := bytes.Compare(fa.Uniq[:], fb.Uniq[:]); c != 0 {
+		return c
+	}
+	if c := compareTerms(fa.Pid, fb.Pid, exact); c != 0 {
+		return c
+	}
+	if c := compareInts(len(fa.Free), len(fb.Free)); c != 0 {
+		return c
+	}
+	for i := range fa.Free {
+		if c := compareTerms(fa.Free[i], fb.Free[i], exact); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareIdentifiers orders pids and ports by their node first and the given
+// fields afterwards, most significant first.
+func compareIdentifiers(na, nb Atom, fa, fb []uint64) int {
+	if c := compareStrings(string(na), string(nb)); c != 0 {
+		return c
+	}
+	for i := range fa {
+		switch {
+		case fa[i] < fb[i]:
+			return -1
+		case fa[i] > fb[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// compareReferences compares the ID words starting with the most significant
+// one, which is the last on the wire.
+func compareReferences(a, b Reference) int {
+	if c := compareStrings(string(a.Node), string(b.Node)); c != 0 {
+		return c
+	}
+	if c := compareInts(len(a.IDs), len(b.IDs)); c != 0 {
+		return c
+	}
+	for i := len(a.IDs) - 1; i >= 0; i-- {
+		switch {
+		case a.IDs[i] < b.IDs[i]:
+			return -1
+		case a.IDs[i] > b.IDs[i]:
+			return 1
+		}
+	}
+	switch {
+	case a.Creation < b.Creation:
+		return -1
+	case a.Creation > b.Creation:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareMaps(a, b Map, exact bool) int {
+	if a.Len() != b.Len() {
+		return compareInts(a.Len(), b.Len())
+	}
+
+	pa, pb := a.SortedPairs(), b.SortedPairs()
+	for i := range pa {
+		if c := compareTerms(pa[i].Key, pb[i].Key, true); c != 0 {
+			return c
+		}
+	}
+	for i := range pa {
+		if c := compareTerms(pa[i].Value, pb[i].Value, exact); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func compareLists(a, b Cons, exact bool) int {
+	var x, y Term = a, b
+
+	for {
+		xc, xIsCons := x.(Cons)
+		yc, yIsCons := y.(Cons)
+
+		if !xIsCons || !yIsCons {
+			return compareTerms(x, y, exact)
+		}
+		if c := compareTerms(xc.this, yc.this, exact); c != 0 {
+			return c
+		}
+		x, y = xc.next, yc.next
+	}
+}
+
+func toBitString(t Term) BitString {
+	switch bs := t.(type) {
+	case Binary:
+		return BitString{Bytes: bs, Bits: 8}
+	default:
+		return bs.(BitString)
+	}
+}
+
+// compareBitStrings compares bit by bit, a bitstring that is a prefix of the
+// other is the smaller one.
+func compareBitStrings(a, b BitString) int {
+	la, lb := a.BitLen(), b.BitLen()
+	common := la
+	if lb < common {
+		common = lb
+	}
+
+	full := common / 8
+	if c := bytes.Compare(a.Bytes[:full], b.Bytes[:full]); c != 0 {
+		return c
+	}
+
+	if rest := uint(common % 8); rest != 0 {
+		mask := byte(0xff << (8 - rest))
+		if c := compareInts(int(a.Bytes[full]&mask), int(b.Bytes[full]&mask)); c != 0 {
+			return c
+		}
+	}
+
+	return compareInts(la, lb)
+}
@@ -0,0 +1,120 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"math"
+	"math/big"
+	"sort"
+	"testing"
+)
+
+var compareTestTable = []struct {
+	Name        string
+	A, B        erlgo.Term
+	Expect      int
+	ExpectExact int
+}{
+	{"1 == 1.0", erlgo.Int64(1), erlgo.Float(1), 0, -1},
+	{"1.0 == 1", erlgo.Float(1), erlgo.Int64(1), 0, 1},
+	{"1 < 1.5", erlgo.Int64(1), erlgo.Float(1.5), -1, -1},
+	{"2 > 1.0", erlgo.Int64(2), erlgo.Float(1), 1, -1},
+	{"0.5 < 3", erlgo.Float(0.5), erlgo.Int64(3), -1, 1},
+	{"big == float", erlgo.IntBig{new(big.Int).Lsh(big.NewInt(1), 70)}, erlgo.Float(1 << 70), 0, -1},
+	{"big > int", erlgo.IntBig{new(big.Int).Lsh(big.NewInt(1), 70)}, erlgo.Int64(1 << 62), 1, 1},
+	{"NaN > float", erlgo.Float(math.NaN()), erlgo.Float(math.Inf(1)), 1, 1},
+	{"NaN > int", erlgo.Float(math.NaN()), erlgo.Int64(1), 1, 1},
+	{"NaN == NaN", erlgo.Float(math.NaN()), erlgo.Float(math.NaN()), 0, 0},
+	{"infinity > big", erlgo.Float(math.Inf(1)), erlgo.IntBig{new(big.Int).Lsh(big.NewInt(1), 70)}, 1, 1},
+	{"number < atom", erlgo.Float(1e300), erlgo.Atom(""), -1, -1},
+	{"atom order", erlgo.Atom("abc"), erlgo.Atom("abd"), -1, -1},
+	{"atom < reference", erlgo.Atom("zzz"), erlgo.Reference{Node: "a@b", IDs: []uint32{1}}, -1, -1},
+	{"reference < fun", erlgo.Reference{Node: "a@b", IDs: []uint32{1}}, erlgo.Export{Module: "m", Function: "f"}, -1, -1},
+	{"fun < port", erlgo.Export{Module: "m", Function: "f"}, erlgo.Port{Node: "a@b"}, -1, -1},
+	{"port < pid", erlgo.Port{Node: "a@b", ID: 100}, erlgo.Pid{Node: "a@b"}, -1, -1},
+	{"pid < tuple", erlgo.Pid{Node: "a@b", ID: 100}, erlgo.Tuple{}, -1, -1},
+	{"tuple by size", erlgo.Tuple{erlgo.Int64(9)}, erlgo.Tuple{erlgo.Int64(1), erlgo.Int64(1)}, -1, -1},
+	{"tuple with numbers", erlgo.Tuple{erlgo.Int64(1)}, erlgo.Tuple{erlgo.Float(1)}, 0, -1},
+	{"tuple < map", erlgo.Tuple{erlgo.Int64(1), erlgo.Int64(2)}, erlgo.NewMap(), -1, -1},
+	{"map by size", erlgo.NewMap(erlgo.Pair{Key: erlgo.Int64(9), Value: erlgo.Int64(9)}), erlgo.NewMap(erlgo.Pair{Key: erlgo.Int64(1), Value: erlgo.Int64(1)}, erlgo.Pair{Key: erlgo.Int64(2), Value: erlgo.Int64(2)}), -1, -1},
+	{"map keys are exact", erlgo.NewMap(erlgo.Pair{Key: erlgo.Int64(1), Value: erlgo.Int64(1)}), erlgo.NewMap(erlgo.Pair{Key: erlgo.Float(1), Value: erlgo.Int64(1)}), -1, -1},
+	{"map keys by type", erlgo.NewMap(erlgo.Pair{Key: erlgo.Int64(2), Value: erlgo.Int64(1)}), erlgo.NewMap(erlgo.Pair{Key: erlgo.Float(1), Value: erlgo.Int64(1)}), -1, -1},
+	{"map values are not", erlgo.NewMap(erlgo.Pair{Key: erlgo.Int64(1), Value: erlgo.Int64(1)}), erlgo.NewMap(erlgo.Pair{Key: erlgo.Int64(1), Value: erlgo.Float(1)}), 0, -1},
+	{"map < nil", erlgo.NewMap(), erlgo.Nil{}, -1, -1},
+	{"nil < list", erlgo.Nil{}, erlgo.NewCons(erlgo.Int64(1), erlgo.Nil{}), -1, -1},
+	{"list prefix", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1)}), erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(2)}), -1, -1},
+	{"improper list", erlgo.NewCons(erlgo.Int64(1), erlgo.Int64(2)), erlgo.NewCons(erlgo.Int64(1), erlgo.Nil{}), -1, -1},
+	{"list < binary", erlgo.NewCons(erlgo.Int64(1), erlgo.Nil{}), erlgo.Binary{}, -1, -1},
+	{"binary order", erlgo.Binary("ab"), erlgo.Binary("b"), -1, -1},
+	{"binary prefix", erlgo.Binary("a"), erlgo.Binary("ab"), -1, -1},
+	{"bitstring prefix", erlgo.BitString{Bytes: []byte{0x80}, Bits: 1}, erlgo.Binary{0x80}, -1, -1},
+	{"equal binaries", erlgo.Binary("abc"), erlgo.BitString{Bytes: []byte("abc"), Bits: 8}, 0, 0},
+}
+
+func TestCompare(t *testing.T) {
+	for _, test := range compareTestTable {
+		if c := erlgo.Compare(test.A, test.B); c != test.Expect {
+			t.Errorf(`%v: Compare(%#v, %#v) = %v, expected %v.`, test.Name, test.A, test.B, c, test.Expect)
+		}
+		if c := erlgo.Compare(test.B, test.A); c != -test.Expect {
+			t.Errorf(`%v: Compare(%#v, %#v) = %v, expected %v.`, test.Name, test.B, test.A, c, -test.Expect)
+		}
+		if c := erlgo.CompareExact(test.A, test.B); c != test.ExpectExact {
+			t.Errorf(`%v: CompareExact(%#v, %#v) = %v, expected %v.`, test.Name, test.A, test.B, c, test.ExpectExact)
+		}
+		if eq := erlgo.Equal(test.A, test.B); eq != (test.Expect == 0) {
+			t.Errorf(`%v: Equal(%#v, %#v) = %v, expected %v.`, test.Name, test.A, test.B, eq, test.Expect == 0)
+		}
+		if eq := erlgo.ExactEqual(test.A, test.B); eq != (test.ExpectExact == 0) {
+			t.Errorf(`%v: ExactEqual(%#v, %#v) = %v, expected %v.`, test.Name, test.A, test.B, eq, test.ExpectExact == 0)
+		}
+	}
+}
+
+func TestSortingDecodedNaN(t *testing.T) {
+	nan, err := erlgo.FromBytes([]byte{131, 70, 0x7f, 0xf8, 0, 0, 0, 0, 0, 1}).Decode()
+	if err != nil {
+		t.Fatalf(`decoding NaN encountered error "%v".`, err)
+	}
+
+	m := erlgo.NewMap(erlgo.Pair{Key: nan, Value: erlgo.Nil{}}, erlgo.Pair{Key: erlgo.Int64(1), Value: erlgo.Nil{}})
+	if pairs := m.SortedPairs(); len(pairs) != 2 || !pairs[0].Key.Matches(erlgo.Int64(1)) {
+		t.Errorf(`sorted into %v, expected 1 first.`, pairs)
+	}
+}
+
+func TestSortingTerms(t *testing.T) {
+	expect := []erlgo.Term{
+		erlgo.Int64(-1),
+		erlgo.Float(0.5),
+		erlgo.Atom("a"),
+		erlgo.Reference{Node: "a@b", IDs: []uint32{1}},
+		erlgo.Export{Module: "m", Function: "f"},
+		erlgo.Port{Node: "a@b"},
+		erlgo.Pid{Node: "a@b"},
+		erlgo.Tuple{},
+		erlgo.NewMap(),
+		erlgo.Nil{},
+		erlgo.NewCons(erlgo.Int64(1), erlgo.Nil{}),
+		erlgo.Binary("a"),
+	}
+
+	terms := make([]erlgo.Term, len(expect))
+	for i := range expect {
+		terms[i] = expect[(i*5+3)%len(expect)]
+	}
+
+	sort.Slice(terms, func(i, j int) bool { return erlgo.Compare(terms[i], terms[j]) < 0 })
+	for i := range expect {
+		if !terms[i].Matches(expect[i]) {
+			t.Errorf(`sorted %#v at %v, expected %#v.`, terms[i], i, expect[i])
+		}
+	}
+}
+
+func BenchmarkCompare(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, test := range compareTestTable {
+			erlgo.Compare(test.A, test.B)
+		}
+	}
+}
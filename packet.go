@@ -0,0 +1,130 @@
+package erlgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// PacketReader reads packets prefixed with a big endian length of 1, 2 or 4
+// bytes, as sent to a port opened with `{packet, N}`.
+type PacketReader struct {
+	r       io.Reader
+	size    int
+	maxSize int
+}
+
+func NewPacketReader(r io.Reader, size int) *PacketReader {
+	return &PacketReader{r: r, size: size}
+}
+
+// SetMaxSize makes ReadPacket reject packets longer than max bytes before
+// reading them, the stream can not be read any further after that. A maximum
+// of zero means there is no limit, the default.
+func (p *PacketReader) SetMaxSize(max int) {
+	p.maxSize = max
+}
+
+// ReadPacket returns the payload of the next packet. It returns io.EOF if the
+// stream ends between packets and io.ErrUnexpectedEOF if it ends within one.
+func (p *PacketReader) ReadPacket() ([]byte, error) {
+	if err := checkPacketSize(p.size); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, p.size)
+	if _, err := io.ReadFull(p.r, header); err != nil {
+		return nil, err
+	}
+
+	var length uint32
+	for _, b := range header {
+		length = length<<8 | uint32(b)
+	}
+
+	if p.maxSize > 0 && uint64(length) > uint64(p.maxSize) {
+		return nil, fmt.Errorf("packet of %v bytes exceeds the maximum of %v bytes", length, p.maxSize)
+	}
+
+	data, err := readFull(p.r, int(length))
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Decode reads the next packet and decodes the single term it contains.
+func (p *PacketReader) Decode() (Term, error) {
+	data, err := p.ReadPacket()
+	if err != nil {
+		return nil, err
+	}
+
+	b := FromBytes(data)
+	term, err := b.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := b.bs.Peek(1); err != io.EOF {
+		return nil, fmt.Errorf("packet of %v bytes has data after the term", len(data))
+	}
+
+	return term, nil
+}
+
+// PacketWriter writes packets prefixed with a big endian length of 1, 2 or 4
+// bytes, as expected from a port opened with `{packet, N}`.
+type PacketWriter struct {
+	w    io.Writer
+	size int
+}
+
+func NewPacketWriter(w io.Writer, size int) *PacketWriter {
+	return &PacketWriter{w: w, size: size}
+}
+
+// WritePacket writes data as a single packet, which fails if data is too long
+// to have its length expressed in the header.
+func (p *PacketWriter) WritePacket(data []byte) error {
+	if err := checkPacketSize(p.size); err != nil {
+		return err
+	}
+
+	if uint64(len(data)) >= 1<<uint(8*p.size) {
+		return fmt.Errorf("%v bytes do not fit into a packet with a %v byte header", len(data), p.size)
+	}
+
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+
+	var buf bytes.Buffer
+	buf.Write(header[4-p.size:])
+	buf.Write(data)
+
+	_, err := buf.WriteTo(p.w)
+	return err
+}
+
+// Encode writes the term as a single packet.
+func (p *PacketWriter) Encode(t Term) error {
+	data, err := Encode(t)
+	if err != nil {
+		return err
+	}
+
+	return p.WritePacket(data)
+}
+
+func checkPacketSize(size int) error {
+	switch size {
+	case 1, 2, 4:
+		return nil
+	default:
+		return fmt.Errorf("%v is not a valid packet header size", size)
+	}
+}
@@ -0,0 +1,110 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"io"
+	"testing"
+)
+
+var packetTestTable = []struct {
+	Name   string
+	Size   int
+	Data   []byte
+	Expect []byte
+}{
+	{"empty {packet, 1}", 1, []byte{}, []byte{0}},
+	{"empty {packet, 2}", 2, []byte{}, []byte{0, 0}},
+	{"empty {packet, 4}", 4, []byte{}, []byte{0, 0, 0, 0}},
+	{"{packet, 1}", 1, []byte{131, 97, 1}, []byte{3, 131, 97, 1}},
+	{"{packet, 2}", 2, []byte{131, 97, 1}, []byte{0, 3, 131, 97, 1}},
+	{"{packet, 4}", 4, []byte{131, 97, 1}, []byte{0, 0, 0, 3, 131, 97, 1}},
+	{"255 bytes {packet, 1}", 1, make([]byte, 255), append([]byte{255}, make([]byte, 255)...)},
+	{"256 bytes {packet, 2}", 2, make([]byte, 256), append([]byte{1, 0}, make([]byte, 256)...)},
+}
+
+func TestWritingPackets(t *testing.T) {
+	for _, test := range packetTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := erlgo.NewPacketWriter(&buf, test.Size).WritePacket(test.Data); err != nil {
+				t.Errorf(`encountered error "%v", expected %v.`, err, test.Expect)
+			} else if !bytes.Equal(buf.Bytes(), test.Expect) {
+				t.Errorf(`wrote %v, expected %v.`, buf.Bytes(), test.Expect)
+			}
+		})
+	}
+}
+
+func TestReadingPackets(t *testing.T) {
+	for _, test := range packetTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			r := erlgo.NewPacketReader(bytes.NewReader(test.Expect), test.Size)
+			if data, err := r.ReadPacket(); err != nil {
+				t.Errorf(`encountered error "%v", expected %v.`, err, test.Data)
+			} else if !bytes.Equal(data, test.Data) {
+				t.Errorf(`read %v, expected %v.`, data, test.Data)
+			}
+			if data, err := r.ReadPacket(); err != io.EOF {
+				t.Errorf(`read (%v, %v) at the end, expected io.EOF.`, data, err)
+			}
+		})
+	}
+}
+
+func TestPacketErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := erlgo.NewPacketWriter(&buf, 1).WritePacket(make([]byte, 256)); err == nil {
+		t.Errorf(`writing 256 bytes with {packet, 1} succeeded, expected an error.`)
+	}
+	if err := erlgo.NewPacketWriter(&buf, 3).WritePacket([]byte{}); err == nil {
+		t.Errorf(`writing with {packet, 3} succeeded, expected an error.`)
+	}
+	if _, err := erlgo.NewPacketReader(bytes.NewReader([]byte{0, 0}), 3).ReadPacket(); err == nil {
+		t.Errorf(`reading with {packet, 3} succeeded, expected an error.`)
+	}
+	if _, err := erlgo.NewPacketReader(bytes.NewReader([]byte{0}), 2).ReadPacket(); err != io.ErrUnexpectedEOF {
+		t.Errorf(`reading a truncated header returned "%v", expected io.ErrUnexpectedEOF.`, err)
+	}
+	if _, err := erlgo.NewPacketReader(bytes.NewReader([]byte{3, 131, 97}), 1).ReadPacket(); err != io.ErrUnexpectedEOF {
+		t.Errorf(`reading a truncated payload returned "%v", expected io.ErrUnexpectedEOF.`, err)
+	}
+	if _, err := erlgo.NewPacketReader(bytes.NewReader([]byte{255, 255, 255, 255, 131}), 4).ReadPacket(); err != io.ErrUnexpectedEOF {
+		t.Errorf(`reading a truncated payload of 4 GiB returned "%v", expected io.ErrUnexpectedEOF.`, err)
+	}
+	if _, err := erlgo.NewPacketReader(bytes.NewReader([]byte{4, 131, 97, 1, 0}), 1).Decode(); err == nil {
+		t.Errorf(`decoding a packet with trailing data succeeded, expected an error.`)
+	}
+}
+
+func TestPacketMaxSize(t *testing.T) {
+	r := erlgo.NewPacketReader(bytes.NewReader([]byte{0, 0, 0, 3, 131, 97, 1, 0, 0, 0, 4, 131, 97, 1, 0}), 4)
+	r.SetMaxSize(3)
+	if data, err := r.ReadPacket(); err != nil {
+		t.Errorf(`encountered error "%v", expected [131 97 1].`, err)
+	} else if !bytes.Equal(data, []byte{131, 97, 1}) {
+		t.Errorf(`read %v, expected [131 97 1].`, data)
+	}
+	if data, err := r.ReadPacket(); err == nil {
+		t.Errorf(`read %v from a packet of 4 bytes, expected an error.`, data)
+	}
+}
+
+func TestPacketTerms(t *testing.T) {
+	var buf bytes.Buffer
+	w := erlgo.NewPacketWriter(&buf, 2)
+	for _, term := range decoderTerms {
+		if err := w.Encode(term); err != nil {
+			t.Fatalf(`%#v encountered error "%v" while encoding.`, term, err)
+		}
+	}
+
+	r := erlgo.NewPacketReader(&buf, 2)
+	for _, expect := range decoderTerms {
+		if val, err := r.Decode(); err != nil {
+			t.Fatalf(`encountered error "%v", expected %#v.`, err, expect)
+		} else if !val.Matches(expect) {
+			t.Errorf(`decoded %#v, expected %#v.`, val, expect)
+		}
+	}
+}
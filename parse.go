@@ -0,0 +1,971 @@
+package erlgo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ParseError reports the position in the text where parsing a term failed.
+type ParseError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("erlgo: parsing line %d column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseTerm parses a term written in Erlang syntax, optionally followed by
+// a dot. Only literals are understood, that is numbers, atoms, strings,
+// binaries, tuples, lists, maps and external funs like `fun lists:map/2`.
+func ParseTerm(text string) (Term, error) {
+	p, err := newParser(text)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenDot {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	if p.tok.kind != tokenEOF {
+		return nil, p.errorf("unexpected %v after the term", p.tok)
+	}
+
+	return t, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenInt
+	tokenFloat
+	tokenAtom
+	tokenVar
+	tokenString
+	tokenPunct
+	tokenDot
+)
+
+type token struct {
+	kind      tokenKind
+	text      string
+	chars     []rune // of strings, unlike text keeping surrogate code points
+	int       *big.Int
+	float     float64
+	line, col int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of input"
+	case tokenDot:
+		return "dot"
+	case tokenString:
+		return quoteString(t.text)
+	case tokenPunct:
+		return "'" + t.text + "'"
+	default:
+		return t.text
+	}
+}
+
+// scanner splits Erlang source into tokens, similar to erl_scan.
+type scanner struct {
+	src       []rune
+	pos       int
+	line, col int
+}
+
+func newScanner(text string) *scanner {
+	return &scanner{src: []rune(text), line: 1, col: 1}
+}
+
+func (s *scanner) peek(ahead int) rune {
+	if s.pos+ahead >= len(s.src) {
+		return -1
+	}
+	return s.src[s.pos+ahead]
+}
+
+func (s *scanner) advance() rune {
+	r := s.src[s.pos]
+	s.pos++
+	if r == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+	return r
+}
+
+func (s *scanner) errorf(format string, args ...interface{}) error {
+	return &ParseError{Line: s.line, Column: s.col, Err: fmt.Errorf(format, args...)}
+}
+
+var punctuation = []string{"<<", ">>", "=>", "||", "{", "}", "[", "]", "(", ")", ",", "|", "#", ":", "/", "-", "+"}
+
+func (s *scanner) scan() (token, error) {
+	s.skipSpace()
+
+	tok := token{line: s.line, col: s.col}
+	r := s.peek(0)
+
+	switch {
+	case r < 0:
+		tok.kind = tokenEOF
+		return tok, nil
+	case r >= '0' && r <= '9':
+		return s.scanNumber(tok)
+	case r == '$':
+		s.advance()
+		c, err := s.scanChar()
+		if err != nil {
+			return tok, err
+		}
+		tok.kind, tok.text, tok.int = tokenInt, "$"+string(c), big.NewInt(int64(c))
+		return tok, nil
+	case isLowerLatin1(r):
+		tok.kind, tok.text = tokenAtom, s.scanName()
+		if reservedWords[tok.text] && tok.text != "fun" {
+			return tok, &ParseError{Line: tok.line, Column: tok.col, Err: fmt.Errorf("reserved word %v is not an atom", tok.text)}
+		}
+		return tok, nil
+	case isUpperLatin1(r) || r == '_':
+		tok.kind, tok.text = tokenVar, s.scanName()
+		return tok, nil
+	case r == '\'':
+		chars, err := s.scanQuoted('\'')
+		tok.kind, tok.text = tokenAtom, string(chars)
+		return tok, err
+	case r == '"':
+		chars, err := s.scanQuoted('"')
+		tok.kind, tok.text, tok.chars = tokenString, string(chars), chars
+		return tok, err
+	case r == '.':
+		if next := s.peek(1); next < 0 || next == '%' || isSpace(next) {
+			s.advance()
+			tok.kind, tok.text = tokenDot, "."
+			return tok, nil
+		}
+	}
+
+	for _, p := range punctuation {
+		if s.hasPrefix(p) {
+			for range p {
+				s.advance()
+			}
+			tok.kind, tok.text = tokenPunct, p
+			return tok, nil
+		}
+	}
+
+	return tok, s.errorf("unexpected character %q", r)
+}
+
+func (s *scanner) hasPrefix(p string) bool {
+	for i, r := range p {
+		if s.peek(i) != r {
+			return false
+		}
+	}
+	return true
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == '\v' || r == 0xa0
+}
+
+func isLowerLatin1(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 0xdf && r <= 0xff && r != 0xf7
+}
+
+func isUpperLatin1(r rune) bool {
+	return r >= 'A' && r <= 'Z' || r >= 0xc0 && r <= 0xde && r != 0xd7
+}
+
+func (s *scanner) skipSpace() {
+	for s.pos < len(s.src) {
+		if r := s.peek(0); isSpace(r) {
+			s.advance()
+		} else if r == '%' {
+			for s.pos < len(s.src) && s.peek(0) != '\n' {
+				s.advance()
+			}
+		} else {
+			return
+		}
+	}
+}
+
+func (s *scanner) scanName() string {
+	start := s.pos
+	for r := s.peek(0); isLowerLatin1(r) || isUpperLatin1(r) || r >= '0' && r <= '9' || r == '_' || r == '@'; r = s.peek(0) {
+		s.advance()
+	}
+	return string(s.src[start:s.pos])
+}
+
+// scanDigits reads digits of the base, which may be separated by single
+// underscores.
+func (s *scanner) scanDigits(base int) string {
+	var sb strings.Builder
+	for {
+		r := s.peek(0)
+		if digitValue(r) < base {
+			sb.WriteRune(s.advance())
+		} else if r == '_' && sb.Len() > 0 && digitValue(s.peek(1)) < base {
+			s.advance()
+		} else {
+			return sb.String()
+		}
+	}
+}
+
+func digitValue(r rune) int {
+	switch {
+	case r >= '0' && r <= '9':
+		return int(r - '0')
+	case r >= 'a' && r <= 'z':
+		return int(r-'a') + 10
+	case r >= 'A' && r <= 'Z':
+		return int(r-'A') + 10
+	default:
+		return 36
+	}
+}
+
+func (s *scanner) scanNumber(tok token) (token, error) {
+	digits := s.scanDigits(10)
+
+	if s.peek(0) == '#' {
+		base, _ := strconv.Atoi(digits)
+		if base < 2 || base > 36 {
+			return tok, s.errorf("base %v is not between 2 and 36", base)
+		}
+		s.advance()
+
+		based := s.scanDigits(base)
+		if based == "" {
+			return tok, s.errorf("missing digits of base %v", base)
+		}
+
+		tok.kind, tok.text = tokenInt, digits+"#"+based
+		tok.int, _ = new(big.Int).SetString(based, base)
+		return tok, nil
+	}
+
+	if s.peek(0) == '.' && digitValue(s.peek(1)) < 10 {
+		s.advance()
+		digits += "." + s.scanDigits(10)
+
+		if r := s.peek(0); r == 'e' || r == 'E' {
+			sign := s.peek(1)
+			if digitValue(sign) < 10 || (sign == '+' || sign == '-') && digitValue(s.peek(2)) < 10 {
+				s.advance()
+				digits += "e"
+				if sign == '+' || sign == '-' {
+					digits += string(s.advance())
+				}
+				digits += s.scanDigits(10)
+			}
+		}
+
+		f, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return tok, s.errorf("bad float %v", digits)
+		}
+		tok.kind, tok.text, tok.float = tokenFloat, digits, f
+		return tok, nil
+	}
+
+	tok.kind, tok.text = tokenInt, digits
+	tok.int, _ = new(big.Int).SetString(digits, 10)
+	return tok, nil
+}
+
+func (s *scanner) scanQuoted(quote rune) ([]rune, error) {
+	s.advance()
+
+	chars := []rune{}
+	for {
+		switch r := s.peek(0); r {
+		case -1:
+			return nil, s.errorf("unterminated %c", quote)
+		case quote:
+			s.advance()
+			return chars, nil
+		default:
+			c, err := s.scanChar()
+			if err != nil {
+				return nil, err
+			}
+			chars = append(chars, c)
+		}
+	}
+}
+
+var charEscapes = map[rune]rune{
+	'b': '\b', 'd': 127, 'e': 27, 'f': '\f', 'n': '\n', 'r': '\r', 's': ' ', 't': '\t', 'v': '\v',
+}
+
+// scanChar reads a single, possibly escaped, character.
+func (s *scanner) scanChar() (rune, error) {
+	if s.peek(0) < 0 {
+		return 0, s.errorf("unexpected end of input")
+	} else if s.peek(0) != '\\' {
+		return s.advance(), nil
+	}
+
+	s.advance()
+	r := s.peek(0)
+	switch {
+	case r < 0:
+		return 0, s.errorf("unexpected end of input")
+	case r >= '0' && r <= '7':
+		c := 0
+		for i := 0; i < 3 && s.peek(0) >= '0' && s.peek(0) <= '7'; i++ {
+			c = c*8 + int(s.advance()-'0')
+		}
+		return rune(c), nil
+	case r == 'x':
+		s.advance()
+		var hex string
+		if s.peek(0) == '{' {
+			s.advance()
+			hex = s.scanDigits(16)
+			if s.peek(0) != '}' {
+				return 0, s.errorf("unterminated \\x{ escape")
+			}
+			s.advance()
+		} else {
+			for i := 0; i < 2 && digitValue(s.peek(0)) < 16; i++ {
+				hex += string(s.advance())
+			}
+		}
+		c, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || c > maxCodePoint {
+			return 0, s.errorf("bad \\x escape %q", hex)
+		}
+		return rune(c), nil
+	case r == '^':
+		s.advance()
+		if s.peek(0) < 0 {
+			return 0, s.errorf("unexpected end of input")
+		}
+		return s.advance() & 31, nil
+	default:
+		s.advance()
+		if c, ok := charEscapes[r]; ok {
+			return c, nil
+		}
+		return r, nil
+	}
+}
+
+const maxCodePoint = 0x10ffff
+
+// parser builds terms from the tokens of a scanner, it always holds the next
+// token in tok.
+type parser struct {
+	s   *scanner
+	tok token
+}
+
+func newParser(text string) (*parser, error) {
+	p := &parser{s: newScanner(text)}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *parser) next() (err error) {
+	p.tok, err = p.s.scan()
+	return err
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Line: p.tok.line, Column: p.tok.col, Err: fmt.Errorf(format, args...)}
+}
+
+func (p *parser) isPunct(text string) bool {
+	return p.tok.kind == tokenPunct && p.tok.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.isPunct(text) {
+		return p.errorf("expected '%v', found %v", text, p.tok)
+	}
+	return p.next()
+}
+
+func (p *parser) term() (Term, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case tokenInt, tokenFloat:
+		return p.number()
+	case tokenAtom:
+		if tok.text == "fun" {
+			return p.export()
+		}
+		return Atom(tok.text), p.next()
+	case tokenString:
+		s, err := p.strings()
+		if err != nil {
+			return nil, err
+		}
+		return stringToList(s), nil
+	case tokenVar:
+		return nil, p.errorf("variable %v is not a term", tok.text)
+	case tokenPunct:
+		switch tok.text {
+		case "-", "+":
+			return p.number()
+		case "{":
+			return p.tuple()
+		case "[":
+			return p.list()
+		case "<<":
+			return p.binary()
+		case "#":
+			return p.mapTerm()
+		}
+	}
+
+	return nil, p.errorf("unexpected %v", tok)
+}
+
+// strings concatenates adjacent string literals, like Erlang does.
+func (p *parser) strings() ([]rune, error) {
+	chars := []rune{}
+	for p.tok.kind == tokenString {
+		chars = append(chars, p.tok.chars...)
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	return chars, nil
+}
+
+func stringToList(chars []rune) Term {
+	terms := make([]Term, len(chars))
+	for i, c := range chars {
+		terms[i] = Int64(c)
+	}
+	return NewListFromTerms(terms)
+}
+
+// number parses a number with an optional sign. Like in erl_parse, a sign can
+// not be applied to another one.
+func (p *parser) number() (Term, error) {
+	negative := false
+	if p.isPunct("-") || p.isPunct("+") {
+		negative = p.tok.text == "-"
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt:
+		i := new(big.Int).Set(tok.int)
+		if negative {
+			i.Neg(i)
+		}
+		return bigToTerm(i), p.next()
+	case tokenFloat:
+		if negative {
+			return Float(-tok.float), p.next()
+		}
+		return Float(tok.float), p.next()
+	default:
+		return nil, p.errorf("expected a number, found %v", tok)
+	}
+}
+
+// bigToTerm returns an Int64 for integers the decoder reads into one, which
+// are those with a magnitude of at most 7 bytes.
+func bigToTerm(i *big.Int) Term {
+	if i.BitLen() <= 56 {
+		return Int64(i.Int64())
+	}
+	return IntBig{i}
+}
+
+func (p *parser) export() (Term, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var names [2]Atom
+	for i := range names {
+		if p.tok.kind != tokenAtom {
+			return nil, p.errorf("expected an atom, found %v", p.tok)
+		}
+		names[i] = Atom(p.tok.text)
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := p.expect("/"); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokenInt || !p.tok.int.IsUint64() || p.tok.int.Uint64() > 255 {
+		return nil, p.errorf("expected an arity, found %v", p.tok)
+	}
+	arity := uint8(p.tok.int.Uint64())
+
+	return Export{Module: names[0], Function: names[1], Arity: arity}, p.next()
+}
+
+// elements parses terms separated by commas up to close, which is consumed.
+// If tail is set, the last element may follow a '|' instead.
+func (p *parser) elements(close string, tail bool) ([]Term, Term, error) {
+	if err := p.next(); err != nil {
+		return nil, nil, err
+	}
+
+	elems := []Term{}
+	if p.isPunct(close) {
+		return elems, nil, p.next()
+	}
+
+	for {
+		t, err := p.term()
+		if err != nil {
+			return nil, nil, err
+		}
+		elems = append(elems, t)
+
+		if tail && p.isPunct("|") {
+			if err := p.next(); err != nil {
+				return nil, nil, err
+			}
+			t, err := p.term()
+			if err != nil {
+				return nil, nil, err
+			}
+			return elems, t, p.expect(close)
+		} else if p.isPunct(close) {
+			return elems, nil, p.next()
+		} else if err := p.expect(","); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+func (p *parser) tuple() (Term, error) {
+	elems, _, err := p.elements("}", false)
+	if err != nil {
+		return nil, err
+	}
+	return Tuple(elems), nil
+}
+
+func (p *parser) list() (Term, error) {
+	elems, tail, err := p.elements("]", true)
+	if err != nil {
+		return nil, err
+	}
+	if tail == nil {
+		tail = Nil{}
+	}
+	return newListWithTail(elems, tail), nil
+}
+
+func (p *parser) mapTerm() (Term, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	} else if !p.isPunct("{") {
+		return nil, p.errorf("expected '{', found %v", p.tok)
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	mb := newMapBuilder(0)
+	if p.isPunct("}") {
+		return mb.result(), p.next()
+	}
+
+	for {
+		key, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("=>"); err != nil {
+			return nil, err
+		}
+		value, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		mb.put(key, value)
+
+		if p.isPunct("}") {
+			return mb.result(), p.next()
+		} else if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// bitBuilder collects the bits of a binary, most significant first.
+type bitBuilder struct {
+	bytes []byte
+	bits  int
+}
+
+func (bb *bitBuilder) writeBit(bit uint) {
+	if bb.bits%8 == 0 {
+		bb.bytes = append(bb.bytes, 0)
+	}
+	bb.bytes[len(bb.bytes)-1] |= byte(bit << uint(7-bb.bits%8))
+	bb.bits++
+}
+
+// writeInt writes the lowest size bits of i in two's complement.
+func (bb *bitBuilder) writeInt(i *big.Int, size int) {
+	m := new(big.Int).Mod(i, new(big.Int).Lsh(big.NewInt(1), uint(size)))
+	for bit := size - 1; bit >= 0; bit-- {
+		bb.writeBit(m.Bit(bit))
+	}
+}
+
+func (bb *bitBuilder) writeBytes(data []byte) {
+	for _, b := range data {
+		bb.writeInt(big.NewInt(int64(b)), 8)
+	}
+}
+
+func (bb *bitBuilder) term() Term {
+	if bb.bits%8 == 0 {
+		return Binary(append([]byte{}, bb.bytes...))
+	}
+	return BitString{Bytes: bb.bytes, Bits: uint8(bb.bits % 8)}
+}
+
+// segment is a parsed binary segment, Value:Size/Type-Endian-unit:Unit.
+type segment struct {
+	value  Term
+	size   int
+	unit   int
+	typ    string
+	little bool
+	line   int
+	col    int
+}
+
+func (p *parser) binary() (Term, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var bb bitBuilder
+	if p.isPunct(">>") {
+		return bb.term(), p.next()
+	}
+
+	for {
+		if err := p.segment(&bb); err != nil {
+			return nil, err
+		}
+
+		if p.isPunct(">>") {
+			return bb.term(), p.next()
+		} else if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) segment(bb *bitBuilder) error {
+	seg := segment{size: -1, line: p.tok.line, col: p.tok.col}
+
+	var chars []rune
+	var err error
+	switch {
+	case p.tok.kind == tokenString:
+		chars, err = p.strings()
+	case p.isPunct("<<"):
+		seg.value, err = p.binary()
+	default:
+		seg.value, err = p.number()
+	}
+	if err != nil {
+		return err
+	}
+
+	if p.isPunct(":") {
+		if err := p.next(); err != nil {
+			return err
+		}
+		if p.tok.kind != tokenInt || !p.tok.int.IsInt64() {
+			return p.errorf("expected a size, found %v", p.tok)
+		}
+		if seg.size = int(p.tok.int.Int64()); seg.size > maxBinaryBits {
+			return p.errorf("segment size %v is too large", p.tok)
+		}
+		if err := p.next(); err != nil {
+			return err
+		}
+	}
+
+	if p.isPunct("/") {
+		if err := p.typeSpecifiers(&seg); err != nil {
+			return err
+		}
+	}
+
+	if chars == nil {
+		return seg.write(bb)
+	}
+
+	for _, c := range chars {
+		seg.value = Int64(c)
+		if err := seg.write(bb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) typeSpecifiers(seg *segment) error {
+	for {
+		if err := p.next(); err != nil {
+			return err
+		} else if p.tok.kind != tokenAtom {
+			return p.errorf("expected a type specifier, found %v", p.tok)
+		}
+
+		switch spec := p.tok.text; spec {
+		case "integer", "float", "binary", "bitstring", "utf8", "utf16", "utf32":
+			seg.typ = spec
+		case "bytes":
+			seg.typ = "binary"
+		case "bits":
+			seg.typ = "bitstring"
+		case "big", "signed", "unsigned":
+		case "little":
+			seg.little = true
+		case "unit":
+			if err := p.next(); err != nil {
+				return err
+			} else if err := p.expect(":"); err != nil {
+				return err
+			}
+			if p.tok.kind != tokenInt || !p.tok.int.IsInt64() || p.tok.int.Int64() < 1 || p.tok.int.Int64() > 256 {
+				return p.errorf("expected a unit between 1 and 256, found %v", p.tok)
+			}
+			seg.unit = int(p.tok.int.Int64())
+		default:
+			return p.errorf("unknown type specifier %v", spec)
+		}
+
+		if err := p.next(); err != nil {
+			return err
+		}
+		if !p.isPunct("-") {
+			return nil
+		}
+	}
+}
+
+func (seg segment) errorf(format string, args ...interface{}) error {
+	return &ParseError{Line: seg.line, Column: seg.col, Err: fmt.Errorf(format, args...)}
+}
+
+func (seg segment) write(bb *bitBuilder) error {
+	typ := seg.typ
+	if typ == "" {
+		typ = "integer"
+		if _, ok := seg.value.(BitString); ok {
+			typ = "bitstring"
+		} else if _, ok := seg.value.(Binary); ok {
+			typ = "binary"
+		}
+	}
+
+	unit := seg.unit
+	if unit == 0 {
+		unit = 1
+		if typ == "binary" {
+			unit = 8
+		}
+	}
+
+	switch typ {
+	case "integer":
+		size := 8
+		if seg.size >= 0 {
+			size = seg.size * unit
+		}
+		if err := seg.checkSize(bb, size); err != nil {
+			return err
+		}
+		i, ok := termToBig(seg.value)
+		if !ok {
+			return seg.errorf("%v is not an integer", seg.value)
+		}
+		return seg.writeInt(bb, i, size)
+	case "float":
+		size := 64
+		if seg.size >= 0 {
+			size = seg.size * unit
+		}
+		var f float64
+		switch v := seg.value.(type) {
+		case Float:
+			f = float64(v)
+		default:
+			i, ok := termToBig(v)
+			if !ok {
+				return seg.errorf("%v is not a number", seg.value)
+			}
+			f, _ = new(big.Float).SetInt(i).Float64()
+		}
+		switch size {
+		case 32:
+			return seg.writeInt(bb, new(big.Int).SetUint64(uint64(math.Float32bits(float32(f)))), 32)
+		case 64:
+			return seg.writeInt(bb, new(big.Int).SetUint64(math.Float64bits(f)), 64)
+		default:
+			return seg.errorf("float size %v is not 32 or 64", size)
+		}
+	case "binary", "bitstring":
+		var bs BitString
+		switch v := seg.value.(type) {
+		case Binary:
+			bs = BitString{Bytes: v, Bits: 8}
+		case BitString:
+			bs = v
+		default:
+			return seg.errorf("%v is not a binary", seg.value)
+		}
+		length := bs.BitLen()
+		if seg.size >= 0 {
+			if seg.size*unit > length {
+				return seg.errorf("binary of %v bits is shorter than %v", length, seg.size*unit)
+			}
+			length = seg.size * unit
+		} else if length%unit != 0 {
+			return seg.errorf("binary of %v bits is not a multiple of the unit %v", length, unit)
+		}
+		if err := seg.checkSize(bb, length); err != nil {
+			return err
+		}
+		for i := 0; i < length; i++ {
+			bb.writeBit(uint(bs.Bytes[i/8]>>uint(7-i%8)) & 1)
+		}
+		return nil
+	default:
+		if seg.size >= 0 || seg.unit != 0 {
+			return seg.errorf("%v segments have no size", typ)
+		}
+		i, ok := termToBig(seg.value)
+		if !ok || !i.IsInt64() || i.Int64() < 0 || i.Int64() > maxCodePoint || isSurrogate(i.Int64()) {
+			return seg.errorf("%v is not a code point", seg.value)
+		}
+		return seg.writeChar(bb, typ, rune(i.Int64()))
+	}
+}
+
+// maxBinaryBits limits the size of binaries, so that a size in the text can not
+// make the parser allocate memory out of proportion to the text.
+const maxBinaryBits = 1 << 27
+
+func (seg segment) checkSize(bb *bitBuilder, size int) error {
+	if bb.bits+size > maxBinaryBits {
+		return seg.errorf("binary of more than %v bits is too large", maxBinaryBits)
+	}
+	return nil
+}
+
+// isSurrogate reports whether c is reserved for UTF-16 surrogate pairs, which
+// can not be encoded in the UTF encodings.
+func isSurrogate(c int64) bool {
+	return c >= 0xd800 && c <= 0xdfff
+}
+
+func (seg segment) writeInt(bb *bitBuilder, i *big.Int, size int) error {
+	if !seg.little || size <= 8 {
+		bb.writeInt(i, size)
+		return nil
+	} else if size%8 != 0 {
+		return seg.errorf("little endian segments of %v bits are not supported", size)
+	}
+
+	var be bitBuilder
+	be.writeInt(i, size)
+	for j := len(be.bytes) - 1; j >= 0; j-- {
+		bb.writeBytes(be.bytes[j : j+1])
+	}
+	return nil
+}
+
+func (seg segment) writeChar(bb *bitBuilder, typ string, c rune) error {
+	var data []byte
+	switch typ {
+	case "utf8":
+		data = make([]byte, utf8.RuneLen(c))
+		utf8.EncodeRune(data, c)
+	case "utf16":
+		units := utf16.Encode([]rune{c})
+		for _, u := range units {
+			if seg.little {
+				data = append(data, byte(u), byte(u>>8))
+			} else {
+				data = append(data, byte(u>>8), byte(u))
+			}
+		}
+	case "utf32":
+		data = make([]byte, 4)
+		if seg.little {
+			binary.LittleEndian.PutUint32(data, uint32(c))
+		} else {
+			binary.BigEndian.PutUint32(data, uint32(c))
+		}
+	}
+	bb.writeBytes(data)
+	return nil
+}
+
+func termToBig(t Term) (*big.Int, bool) {
+	switch v := t.(type) {
+	case Int64:
+		return big.NewInt(int64(v)), true
+	case IntBig:
+		return v.Int, true
+	default:
+		return nil, false
+	}
+}
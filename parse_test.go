@@ -0,0 +1,142 @@
+package erlgo_test
+
+import (
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+var parseTestTable = []struct {
+	Name   string
+	Text   string
+	Expect erlgo.Term
+}{
+	{"integer", "42", erlgo.Int64(42)},
+	{"negative integer", "-42", erlgo.Int64(-42)},
+	{"underscores", "1_000_000", erlgo.Int64(1000000)},
+	{"hex", "16#FF", erlgo.Int64(255)},
+	{"binary base", "2#1010", erlgo.Int64(10)},
+	{"base 36", "36#zz", erlgo.Int64(1295)},
+	{"char", "$a", erlgo.Int64(97)},
+	{"escaped char", `$\n`, erlgo.Int64(10)},
+	{"space char", "$ ", erlgo.Int64(32)},
+	{"big integer", "1180591620717411303424", erlgo.IntBig{new(big.Int).Lsh(big.NewInt(1), 70)}},
+	{"7 byte integer", "72057594037927935", erlgo.Int64(1<<56 - 1)},
+	{"8 byte integer", "72057594037927936", erlgo.IntBig{big.NewInt(1 << 56)}},
+	{"float", "1.5", erlgo.Float(1.5)},
+	{"negative float", "-0.25", erlgo.Float(-0.25)},
+	{"float exponent", "1.0e-3", erlgo.Float(0.001)},
+	{"atom", "ok", erlgo.Atom("ok")},
+	{"atom with @", "foo@bar", erlgo.Atom("foo@bar")},
+	{"quoted atom", `'Hello world'`, erlgo.Atom("Hello world")},
+	{"quoted atom escapes", `'it\'s'`, erlgo.Atom("it's")},
+	{"string", `"abc"`, erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(97), erlgo.Int64(98), erlgo.Int64(99)})},
+	{"empty string", `""`, erlgo.Nil{}},
+	{"concatenated strings", `"a" "b"`, erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(97), erlgo.Int64(98)})},
+	{"unicode string", `"ä\x{20ac}"`, erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(0xe4), erlgo.Int64(0x20ac)})},
+	{"surrogate string", `"\x{D800}"`, erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(0xd800)})},
+	{"tuple", "{ok, [1, 2, 3]}", erlgo.Tuple{erlgo.Atom("ok"), erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(2), erlgo.Int64(3)})}},
+	{"empty tuple", "{}", erlgo.Tuple{}},
+	{"empty list", "[]", erlgo.Nil{}},
+	{"improper list", "[1, 2 | 3]", erlgo.NewCons(erlgo.Int64(1), erlgo.NewCons(erlgo.Int64(2), erlgo.Int64(3)))},
+	{"list tail", "[1 | [2]]", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(2)})},
+	{"map", "#{a => 1, <<\"b\">> => [2]}", erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)},
+		erlgo.Pair{Key: erlgo.Binary("b"), Value: erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(2)})})},
+	{"empty map", "#{}", erlgo.NewMap()},
+	{"empty binary", "<<>>", erlgo.Binary{}},
+	{"binary string", `<<"bin">>`, erlgo.Binary("bin")},
+	{"binary bytes", "<<1, 2, 255>>", erlgo.Binary{1, 2, 255}},
+	{"binary sizes", "<<1:16, -1:8>>", erlgo.Binary{0, 1, 255}},
+	{"binary little", "<<1:16/little>>", erlgo.Binary{1, 0}},
+	{"binary utf8", `<<"ä"/utf8>>`, erlgo.Binary{0xc3, 0xa4}},
+	{"binary utf16", `<<"a"/utf16-little>>`, erlgo.Binary{'a', 0}},
+	{"binary float", "<<1.5/float>>", erlgo.Binary{0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+	{"binary float32", "<<1.5:32/float>>", erlgo.Binary{0x3f, 0xc0, 0, 0}},
+	{"binary unit", "<<1:2/unit:8>>", erlgo.Binary{0, 1}},
+	{"nested binary", `<<"a", <<"b">>/binary>>`, erlgo.Binary("ab")},
+	{"bitstring", "<<1, 5:3>>", erlgo.BitString{Bytes: []byte{1, 0xa0}, Bits: 3}},
+	{"export", "fun lists:map/2", erlgo.Export{Module: "lists", Function: "map", Arity: 2}},
+	{"trailing dot", "{a, b}.", erlgo.Tuple{erlgo.Atom("a"), erlgo.Atom("b")}},
+	{"comments", "% config\n[a, % first\n b].\n", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Atom("a"), erlgo.Atom("b")})},
+}
+
+func TestParseTerm(t *testing.T) {
+	for _, test := range parseTestTable {
+		if val, err := erlgo.ParseTerm(test.Text); err != nil {
+			t.Errorf(`%v: encountered error "%v", expected %#v.`, test.Name, err, test.Expect)
+		} else if !val.Matches(test.Expect) || reflect.TypeOf(val) != reflect.TypeOf(test.Expect) {
+			t.Errorf(`%v parsed into %#v, expected %#v.`, test.Name, val, test.Expect)
+		}
+	}
+}
+
+func TestParseTermMatchesDecoder(t *testing.T) {
+	for _, test := range []struct {
+		Text string
+		Data []byte
+	}{
+		{"{ok, 1}", []byte{131, 104, 2, 100, 0, 2, 'o', 'k', 97, 1}},
+		{"-1", []byte{131, 98, 255, 255, 255, 255}},
+		{"4294967296", []byte{131, 110, 5, 0, 0, 0, 0, 0, 1}},
+		{"\"ab\"", []byte{131, 107, 0, 2, 'a', 'b'}},
+	} {
+		expect, err := erlgo.FromBytes(test.Data).Decode()
+		if err != nil {
+			t.Fatalf(`decoding %v failed: %v`, test.Data, err)
+		}
+
+		if val, err := erlgo.ParseTerm(test.Text); err != nil {
+			t.Errorf(`%v: encountered error "%v", expected %#v.`, test.Text, err, expect)
+		} else if !reflect.DeepEqual(val, expect) {
+			t.Errorf(`%v parsed into %#v, expected %#v.`, test.Text, val, expect)
+		}
+	}
+}
+
+func TestParseTermErrors(t *testing.T) {
+	for _, test := range []struct {
+		Name         string
+		Text         string
+		Line, Column int
+	}{
+		{"empty", "", 1, 1},
+		{"variable", "{ok, X}", 1, 6},
+		{"unterminated string", `"abc`, 1, 5},
+		{"unclosed tuple", "{a, b", 1, 6},
+		{"trailing input", "a b", 1, 3},
+		{"bad base", "37#1", 1, 3},
+		{"reserved word", "[after]", 1, 2},
+		{"map without arrow", "#{a, b}", 1, 4},
+		{"second line", "[a,\n  ]", 2, 3},
+		{"float segment", "<<1.5>>", 1, 3},
+		{"bad specifier", "<<1/foo>>", 1, 5},
+		{"double sign", "--1", 1, 2},
+		{"surrogate utf8", "<<16#D800/utf8>>", 1, 3},
+		{"surrogate string utf16", `<<"\x{DFFF}"/utf16>>`, 1, 3},
+		{"code point too large", "<<16#110000/utf32>>", 1, 3},
+		{"huge segment size", "<<1:4000000000000>>", 1, 5},
+		{"huge binary", "<<1:100000000/unit:8>>", 1, 3},
+	} {
+		_, err := erlgo.ParseTerm(test.Text)
+
+		var perr *erlgo.ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf(`%v: returned error %v, expected a ParseError.`, test.Name, err)
+		} else if perr.Line != test.Line || perr.Column != test.Column {
+			t.Errorf(`%v: failed at %v:%v, expected %v:%v (%v).`, test.Name, perr.Line, perr.Column, test.Line, test.Column, err)
+		}
+	}
+}
+
+func BenchmarkParseTerm(b *testing.B) {
+	for _, test := range parseTestTable {
+		b.Run(test.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.ParseTerm(test.Text)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package erlgo
+
+import (
+	"io"
+	"os"
+)
+
+// PortServer answers requests from an Erlang node that started the program as
+// a port with `open_port({spawn, Cmd}, [{packet, N}, binary])`. Each received
+// term is passed to Handler, a non-nil result is sent back as the reply.
+type PortServer struct {
+	// PacketSize is the N of `{packet, N}`, which defaults to 4.
+	PacketSize int
+	Handler    func(Term) (Term, error)
+}
+
+// Serve handles requests read from r and writes the replies to w, until r
+// ends, or either the transport or the handler fail. A clean end of the input
+// is not an error.
+func (s *PortServer) Serve(r io.Reader, w io.Writer) error {
+	size := s.PacketSize
+	if size == 0 {
+		size = 4
+	}
+
+	in, out := NewPacketReader(r, size), NewPacketWriter(w, size)
+
+	for {
+		request, err := in.Decode()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		reply, err := s.Handler(request)
+		if err != nil {
+			return err
+		}
+
+		if reply != nil {
+			if err := out.Encode(reply); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ServeStdio serves on stdin and stdout, which is where the port is connected
+// to.
+func (s *PortServer) ServeStdio() error {
+	return s.Serve(os.Stdin, os.Stdout)
+}
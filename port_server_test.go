@@ -0,0 +1,77 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"io"
+	"testing"
+)
+
+func echoHandler(t erlgo.Term) (erlgo.Term, error) {
+	switch t.(type) {
+	case erlgo.Atom:
+		// casts get no reply
+		return nil, nil
+	default:
+		return erlgo.Tuple{erlgo.Atom("reply"), t}, nil
+	}
+}
+
+func TestPortServer(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 4} {
+		var in, out bytes.Buffer
+
+		packetSize := size
+		if packetSize == 0 {
+			packetSize = 4
+		}
+
+		w := erlgo.NewPacketWriter(&in, packetSize)
+		for _, term := range []erlgo.Term{erlgo.Int64(1), erlgo.Atom("cast"), erlgo.Binary("two")} {
+			if err := w.Encode(term); err != nil {
+				t.Fatalf(`%#v encountered error "%v" while encoding.`, term, err)
+			}
+		}
+
+		server := erlgo.PortServer{PacketSize: size, Handler: echoHandler}
+		if err := server.Serve(&in, &out); err != nil {
+			t.Fatalf(`{packet, %v} server returned error "%v".`, size, err)
+		}
+
+		r := erlgo.NewPacketReader(&out, packetSize)
+		for _, expect := range []erlgo.Term{
+			erlgo.Tuple{erlgo.Atom("reply"), erlgo.Int64(1)},
+			erlgo.Tuple{erlgo.Atom("reply"), erlgo.Binary("two")},
+		} {
+			if val, err := r.Decode(); err != nil {
+				t.Fatalf(`{packet, %v} encountered error "%v", expected %#v.`, size, err, expect)
+			} else if !val.Matches(expect) {
+				t.Errorf(`{packet, %v} replied %#v, expected %#v.`, size, val, expect)
+			}
+		}
+		if val, err := r.Decode(); err != io.EOF {
+			t.Errorf(`{packet, %v} replied (%#v, %v), expected no more replies.`, size, val, err)
+		}
+	}
+}
+
+func TestPortServerHandlerError(t *testing.T) {
+	var in, out bytes.Buffer
+	erlgo.NewPacketWriter(&in, 4).Encode(erlgo.Int64(1))
+
+	failure := errors.New("failure")
+	server := erlgo.PortServer{Handler: func(erlgo.Term) (erlgo.Term, error) { return nil, failure }}
+	if err := server.Serve(&in, &out); err != failure {
+		t.Errorf(`server returned error "%v", expected "%v".`, err, failure)
+	}
+}
+
+func TestPortServerTruncatedInput(t *testing.T) {
+	var out bytes.Buffer
+
+	server := erlgo.PortServer{Handler: echoHandler}
+	if err := server.Serve(bytes.NewReader([]byte{0, 0, 0, 3, 131}), &out); err != io.ErrUnexpectedEOF {
+		t.Errorf(`server returned error "%v", expected io.ErrUnexpectedEOF.`, err)
+	}
+}
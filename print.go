@@ -0,0 +1,540 @@
+package erlgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Terms print in Erlang syntax. %v and %s render like `~w`, %+v like `~p`,
+// which line-wraps at the width, 80 by default, and prints printable lists
+// and binaries as strings. Like io_lib_pretty, it fills lines with the
+// elements of lists and tuples as long as each of them fits on a line,
+// otherwise they go on lines of their own. Unlike io_lib_pretty, tuples
+// tagged with an atom get no layout of their own, so lines may break in
+// other places than in Erlang. A precision limits the depth like `~W` and `~P`
+// do, so `%+.5v` equals `~P` with a depth of 5. Other verbs format the
+// underlying Go value.
+
+const defaultLineWidth = 80
+
+func (i Int64) String() string      { return sprintTerm(i, false, -1) }
+func (i IntBig) String() string     { return sprintTerm(i, false, -1) }
+func (f Float) String() string      { return sprintTerm(f, false, -1) }
+func (a Atom) String() string       { return sprintTerm(a, false, -1) }
+func (t Tuple) String() string      { return sprintTerm(t, false, -1) }
+func (n Nil) String() string        { return sprintTerm(n, false, -1) }
+func (c Cons) String() string       { return sprintTerm(c, false, -1) }
+func (m Map) String() string        { return sprintTerm(m, false, -1) }
+func (b Binary) String() string     { return sprintTerm(b, false, -1) }
+func (bs BitString) String() string { return sprintTerm(bs, false, -1) }
+func (p Pid) String() string        { return sprintTerm(p, false, -1) }
+func (p Port) String() string       { return sprintTerm(p, false, -1) }
+func (r Reference) String() string  { return sprintTerm(r, false, -1) }
+func (f Fun) String() string        { return sprintTerm(f, false, -1) }
+func (e Export) String() string     { return sprintTerm(e, false, -1) }
+
+func (i Int64) Format(s fmt.State, verb rune)      { formatTerm(s, verb, i, int64(i)) }
+func (i IntBig) Format(s fmt.State, verb rune)     { formatTerm(s, verb, i, i.Int) }
+func (f Float) Format(s fmt.State, verb rune)      { formatTerm(s, verb, f, float64(f)) }
+func (a Atom) Format(s fmt.State, verb rune)       { formatTerm(s, verb, a, string(a)) }
+func (t Tuple) Format(s fmt.State, verb rune)      { formatTerm(s, verb, t, nil) }
+func (n Nil) Format(s fmt.State, verb rune)        { formatTerm(s, verb, n, nil) }
+func (c Cons) Format(s fmt.State, verb rune)       { formatTerm(s, verb, c, nil) }
+func (m Map) Format(s fmt.State, verb rune)        { formatTerm(s, verb, m, nil) }
+func (b Binary) Format(s fmt.State, verb rune)     { formatTerm(s, verb, b, []byte(b)) }
+func (bs BitString) Format(s fmt.State, verb rune) { formatTerm(s, verb, bs, bs.Bytes) }
+func (p Pid) Format(s fmt.State, verb rune)        { formatTerm(s, verb, p, nil) }
+func (p Port) Format(s fmt.State, verb rune)       { formatTerm(s, verb, p, nil) }
+func (r Reference) Format(s fmt.State, verb rune)  { formatTerm(s, verb, r, nil) }
+func (f Fun) Format(s fmt.State, verb rune)        { formatTerm(s, verb, f, nil) }
+func (e Export) Format(s fmt.State, verb rune)     { formatTerm(s, verb, e, nil) }
+
+// formatTerm prints t in Erlang syntax for %v and %s, and formats the
+// underlying value for other verbs. Without an underlying value the Erlang
+// syntax is formatted instead.
+func formatTerm(s fmt.State, verb rune, t Term, underlying interface{}) {
+	if verb != 'v' && verb != 's' {
+		if underlying == nil {
+			underlying = sprintTerm(t, false, -1)
+		}
+		fmt.Fprintf(s, fallbackFormat(s, verb), underlying)
+		return
+	}
+
+	depth := -1
+	if prec, ok := s.Precision(); ok {
+		depth = prec
+	}
+
+	pretty := verb == 'v' && s.Flag('+')
+	if !pretty {
+		fmt.Fprint(s, sprintTerm(t, false, depth))
+		return
+	}
+
+	width := defaultLineWidth
+	if w, ok := s.Width(); ok {
+		width = w
+	}
+
+	fmt.Fprint(s, buildDoc(t, true, depth).layout(0, width))
+}
+
+// fallbackFormat rebuilds the format directive from the state.
+func fallbackFormat(s fmt.State, verb rune) string {
+	format := "%"
+	for _, flag := range "+-# 0" {
+		if s.Flag(int(flag)) {
+			format += string(flag)
+		}
+	}
+	if w, ok := s.Width(); ok {
+		format += strconv.Itoa(w)
+	}
+	if p, ok := s.Precision(); ok {
+		format += "." + strconv.Itoa(p)
+	}
+	return format + string(verb)
+}
+
+func sprintTerm(t Term, pretty bool, depth int) string {
+	return buildDoc(t, pretty, depth).flat()
+}
+
+// doc is a term prepared for printing. Leaves only have text, compound terms
+// have their elements between open and close.
+type doc struct {
+	text        string
+	open, close string
+	elems       []docElem
+
+	// fill allows more than one element on a line, once d is wrapped
+	fill bool
+}
+
+// docElem is an element of a compound term, with the separator that precedes
+// it on the same line.
+type docElem struct {
+	sep string
+	doc doc
+}
+
+func leaf(text string) doc {
+	return doc{text: text}
+}
+
+func (d doc) isLeaf() bool {
+	return d.elems == nil && d.open == ""
+}
+
+func (d doc) flat() string {
+	if d.isLeaf() {
+		return d.text
+	}
+
+	var sb strings.Builder
+	sb.WriteString(d.open)
+	for _, e := range d.elems {
+		sb.WriteString(e.sep)
+		sb.WriteString(e.doc.flat())
+	}
+	sb.WriteString(d.close)
+	return sb.String()
+}
+
+// layout puts d on a single line if it fits into the width from column col
+// on, and otherwise its elements on lines indented to the column after the
+// opening bracket.
+func (d doc) layout(col, width int) string {
+	flat := d.flat()
+	if d.isLeaf() || col+utf8.RuneCountInString(flat) <= width {
+		return flat
+	}
+
+	indent := col + utf8.RuneCountInString(d.open)
+	if s, ok := d.fillLines(indent, width); ok {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.WriteString(d.open)
+	for i, e := range d.elems {
+		if i > 0 {
+			sb.WriteString(e.sep)
+			sb.WriteString("\n")
+			sb.WriteString(strings.Repeat(" ", indent))
+		}
+		sb.WriteString(e.doc.layout(indent, width))
+	}
+	sb.WriteString(d.close)
+	return sb.String()
+}
+
+// fillLines puts as many elements on each line as fit, which only works if
+// each of them fits on a line of its own, together with the separator or
+// the closing bracket following it.
+func (d doc) fillLines(indent, width int) (string, bool) {
+	if !d.fill {
+		return "", false
+	}
+
+	lengths := make([]int, len(d.elems))
+	for i, e := range d.elems {
+		trailer := d.close
+		if i+1 < len(d.elems) {
+			trailer = d.elems[i+1].sep
+		}
+		lengths[i] = utf8.RuneCountInString(e.doc.flat()) + utf8.RuneCountInString(trailer)
+		if indent+lengths[i] > width {
+			return "", false
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(d.open)
+	col := indent
+	for i, e := range d.elems {
+		sb.WriteString(e.sep)
+		if i > 0 && col+lengths[i] > width {
+			sb.WriteString("\n")
+			sb.WriteString(strings.Repeat(" ", indent))
+			col = indent
+		}
+		sb.WriteString(e.doc.flat())
+		col += lengths[i]
+	}
+	sb.WriteString(d.close)
+	return sb.String(), true
+}
+
+// buildDoc follows io_lib:write/2 for the depth, a negative depth is no
+// limit.
+func buildDoc(t Term, pretty bool, depth int) doc {
+	if depth == 0 {
+		return leaf("...")
+	}
+
+	switch v := t.(type) {
+	case Int64:
+		return leaf(strconv.FormatInt(int64(v), 10))
+	case IntBig:
+		return leaf(v.Int.String())
+	case Float:
+		return leaf(formatFloat(float64(v)))
+	case Atom:
+		return leaf(quoteAtom(string(v)))
+	case Tuple:
+		if depth == 1 && len(v) > 0 {
+			return leaf("{...}")
+		}
+		return buildSeq("{", "}", []Term(v), nil, pretty, depth)
+	case Nil:
+		return leaf("[]")
+	case Cons:
+		if depth == 1 {
+			return leaf("[...]")
+		}
+		elems, tail := v.Elements()
+		if pretty {
+			if s, ok := printableChars(elems, tail); ok {
+				return leaf(truncateString(s, depth))
+			}
+		}
+		if _, ok := tail.(Nil); ok {
+			tail = nil
+		}
+		return buildSeq("[", "]", elems, tail, pretty, depth)
+	case Map:
+		if depth == 1 && v.Len() > 0 {
+			return leaf("#{...}")
+		}
+		return buildMap(v, pretty, depth)
+	case Binary:
+		return buildBinary(v, 8, pretty, depth)
+	case BitString:
+		if len(v.Bytes) == 0 {
+			return leaf("<<>>")
+		}
+		return buildBinary(v.Bytes, v.Bits, pretty, depth)
+	case Pid:
+		return leaf(fmt.Sprintf("<0.%d.%d>", v.ID, v.Serial))
+	case Port:
+		return leaf(fmt.Sprintf("#Port<0.%d>", v.ID))
+	case Reference:
+		var sb strings.Builder
+		sb.WriteString("#Ref<0")
+		for i := len(v.IDs) - 1; i >= 0; i-- {
+			sb.WriteString("." + strconv.FormatUint(uint64(v.IDs[i]), 10))
+		}
+		sb.WriteString(">")
+		return leaf(sb.String())
+	case Fun:
+		return leaf(fmt.Sprintf("#Fun<%v.%d.%d>", quoteAtom(string(v.Module)), v.OldIndex, v.OldUniq))
+	case Export:
+		return leaf(fmt.Sprintf("fun %v:%v/%d", quoteAtom(string(v.Module)), quoteAtom(string(v.Function)), v.Arity))
+	case nil:
+		return leaf("nil")
+	default:
+		return leaf(fmt.Sprintf("%v", t))
+	}
+}
+
+// buildSeq lays out the elements of tuples and lists. Each element uses up
+// one level of depth, once it is exhausted the rest is elided.
+func buildSeq(open, close string, elems []Term, tail Term, pretty bool, depth int) doc {
+	d := doc{open: open, close: close, elems: []docElem{}, fill: true}
+
+	tailSep := "|"
+	if open == "{" {
+		tailSep = ","
+	}
+
+	for i, elem := range elems {
+		sep := ","
+		if i == 0 {
+			sep = ""
+		}
+
+		if depth > 0 && i > 0 && depth-i <= 1 {
+			d.elems = append(d.elems, docElem{tailSep, leaf("...")})
+			return d
+		}
+
+		d.elems = append(d.elems, docElem{sep, buildDoc(elem, pretty, depth-1-i)})
+	}
+
+	if tail != nil {
+		d.elems = append(d.elems, docElem{"|", buildDoc(tail, pretty, depth-1-len(elems))})
+	}
+
+	return d
+}
+
+func buildMap(m Map, pretty bool, depth int) doc {
+	d := doc{open: "#{", close: "}", elems: []docElem{}}
+
+	for i, p := range m.SortedPairs() {
+		sep := ","
+		if i == 0 {
+			sep = ""
+		}
+
+		if depth > 0 && i > 0 && depth-i <= 1 {
+			d.elems = append(d.elems, docElem{sep, leaf("...")})
+			return d
+		}
+
+		key := buildDoc(p.Key, pretty, depth-1-i).flat()
+		entry := doc{open: key + " => ", elems: []docElem{{"", buildDoc(p.Value, pretty, depth-1-i)}}}
+		d.elems = append(d.elems, docElem{sep, entry})
+	}
+
+	return d
+}
+
+// buildBinary prints the bytes, of which only bits are used of the last one.
+func buildBinary(data []byte, bits uint8, pretty bool, depth int) doc {
+	if len(data) == 0 {
+		return leaf("<<>>")
+	}
+
+	if pretty && bits == 8 && printableBytes(data) {
+		return leaf("<<" + truncateString(quoteString(string(latin1ToRunes(data))), depth) + ">>")
+	}
+
+	parts := make([]string, 0, len(data))
+	for i, b := range data {
+		if depth > 0 && i >= depth-1 {
+			parts = append(parts, "...")
+			break
+		}
+
+		if i == len(data)-1 && bits != 8 {
+			parts = append(parts, fmt.Sprintf("%d:%d", b>>(8-bits), bits))
+		} else {
+			parts = append(parts, strconv.Itoa(int(b)))
+		}
+	}
+
+	return leaf("<<" + strings.Join(parts, ",") + ">>")
+}
+
+// truncateString cuts a quoted string to depth-1 characters, like ~P does.
+func truncateString(quoted string, depth int) string {
+	if depth < 0 {
+		return quoted
+	}
+
+	runes := []rune(quoted[1 : len(quoted)-1])
+	if len(runes) < depth {
+		return quoted
+	}
+
+	// do not cut escape sequences in half
+	n := 0
+	for i := 0; i < depth-1 && n < len(runes); i++ {
+		if runes[n] == '\\' {
+			n++
+		}
+		n++
+	}
+
+	return `"` + string(runes[:n]) + `"...`
+}
+
+// printableChars is io_lib:printable_list for Latin-1.
+func printableChars(elems []Term, tail Term) (string, bool) {
+	if _, ok := tail.(Nil); !ok {
+		return "", false
+	}
+
+	runes := make([]rune, len(elems))
+	for i, elem := range elems {
+		c, ok := elem.(Int64)
+		if !ok || !isPrintable(int64(c)) {
+			return "", false
+		}
+		runes[i] = rune(c)
+	}
+
+	return quoteString(string(runes)), true
+}
+
+func printableBytes(data []byte) bool {
+	for _, b := range data {
+		if !isPrintable(int64(b)) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPrintable(c int64) bool {
+	switch {
+	case c >= 32 && c <= 126, c >= 160 && c <= 255:
+		return true
+	case c == '\n', c == '\r', c == '\t', c == '\v', c == '\b', c == '\f', c == 27:
+		return true
+	default:
+		return false
+	}
+}
+
+func latin1ToRunes(data []byte) []rune {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return runes
+}
+
+var erlangEscapes = map[rune]string{
+	'\n': `\n`, '\r': `\r`, '\t': `\t`, '\v': `\v`, '\b': `\b`, '\f': `\f`, 27: `\e`, '\\': `\\`,
+}
+
+func quoteString(s string) string {
+	return quoteWith(s, '"')
+}
+
+func quoteWith(s string, quote rune) string {
+	var sb strings.Builder
+	sb.WriteRune(quote)
+	for _, r := range s {
+		if esc, ok := erlangEscapes[r]; ok {
+			sb.WriteString(esc)
+		} else if r == quote {
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		} else if r < 32 || r == 127 {
+			sb.WriteString(fmt.Sprintf(`\%o`, r))
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteRune(quote)
+	return sb.String()
+}
+
+var reservedWords = map[string]bool{
+	"after": true, "and": true, "andalso": true, "band": true, "begin": true, "bnot": true, "bor": true,
+	"bsl": true, "bsr": true, "bxor": true, "case": true, "catch": true, "cond": true, "div": true,
+	"else": true, "end": true, "fun": true, "if": true, "let": true, "maybe": true, "not": true,
+	"of": true, "or": true, "orelse": true, "receive": true, "rem": true, "try": true, "when": true,
+	"xor": true,
+}
+
+// quoteAtom quotes the atom unless it starts with a lowercase letter and
+// consists of letters, digits, _ and @ only, all of them in Latin-1.
+func quoteAtom(a string) string {
+	if a == "" || reservedWords[a] {
+		return quoteWith(a, '\'')
+	}
+
+	for i, r := range a {
+		lower := r >= 'a' && r <= 'z' || r >= 0xdf && r <= 0xff && r != 0xf7
+		upper := r >= 'A' && r <= 'Z' || r >= 0xc0 && r <= 0xde && r != 0xd7
+		digit := r >= '0' && r <= '9'
+
+		if i == 0 && !lower || !lower && !upper && !digit && r != '_' && r != '@' {
+			return quoteWith(a, '\'')
+		}
+	}
+
+	return a
+}
+
+// formatFloat prints the shortest representation reading back as the same
+// float, in the notation io_lib_format:fwrite_g/1 chooses.
+func formatFloat(f float64) string {
+	if f == 0 {
+		if 1/f < 0 {
+			return "-0.0"
+		}
+		return "0.0"
+	}
+
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+
+	// d.ddde±x, the digits are 0.dddd times 10^place
+	e := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp := e[:strings.IndexByte(e, 'e')], e[strings.IndexByte(e, 'e')+1:]
+	digits := strings.Replace(mantissa, ".", "", 1)
+	x, _ := strconv.Atoi(exp)
+	place := x + 1
+
+	return sign + insertDecimal(place, digits)
+}
+
+func insertDecimal(place int, digits string) string {
+	l := len(digits)
+	if place == 0 {
+		return "0." + digits
+	} else if place > 0 && place < l {
+		return digits[:place] + "." + digits[place:]
+	}
+
+	expL := strconv.Itoa(place - 1)
+	expDot := 1
+	if l == 1 {
+		expDot = 2
+	}
+	expCost := len(expL) + 1 + expDot
+
+	if place < 0 {
+		if 2-place <= expCost {
+			return "0." + strings.Repeat("0", -place) + digits
+		}
+	} else if place-l+2 <= expCost {
+		return digits + strings.Repeat("0", place-l) + ".0"
+	}
+
+	if l == 1 {
+		return digits + ".0e" + expL
+	}
+	return digits[:1] + "." + digits[1:] + "e" + expL
+}
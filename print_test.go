@@ -0,0 +1,157 @@
+package erlgo_test
+
+import (
+	"fmt"
+	"github.com/NobbZ/erlgo"
+	"math/big"
+	"testing"
+)
+
+func charList(s string) erlgo.Term {
+	var list erlgo.Term = erlgo.Nil{}
+	for i := len(s) - 1; i >= 0; i-- {
+		list = erlgo.NewCons(erlgo.Int64(s[i]), list)
+	}
+	return list
+}
+
+func intList(ints ...int64) erlgo.Term {
+	var list erlgo.Term = erlgo.Nil{}
+	for i := len(ints) - 1; i >= 0; i-- {
+		list = erlgo.NewCons(erlgo.Int64(ints[i]), list)
+	}
+	return list
+}
+
+var printTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Write  string
+	Pretty string
+}{
+	{"integer", erlgo.Int64(-42), "-42", "-42"},
+	{"big integer", erlgo.IntBig{new(big.Int).Lsh(big.NewInt(1), 70)}, "1180591620717411303424", "1180591620717411303424"},
+	{"float", erlgo.Float(1.5), "1.5", "1.5"},
+	{"float zero", erlgo.Float(0), "0.0", "0.0"},
+	{"float integral", erlgo.Float(100), "100.0", "100.0"},
+	{"float large", erlgo.Float(1e300), "1.0e300", "1.0e300"},
+	{"float small", erlgo.Float(0.001), "0.001", "0.001"},
+	{"float tiny", erlgo.Float(1.25e-10), "1.25e-10", "1.25e-10"},
+	{"atom", erlgo.Atom("ok"), "ok", "ok"},
+	{"atom with @", erlgo.Atom("foo@bar"), "foo@bar", "foo@bar"},
+	{"atom uppercase", erlgo.Atom("Foo"), "'Foo'", "'Foo'"},
+	{"atom reserved", erlgo.Atom("receive"), "'receive'", "'receive'"},
+	{"atom empty", erlgo.Atom(""), "''", "''"},
+	{"atom with quote", erlgo.Atom("it's"), `'it\'s'`, `'it\'s'`},
+	{"atom latin1", erlgo.Atom("äpfel"), "äpfel", "äpfel"},
+	{"tuple", erlgo.Tuple{erlgo.Atom("ok"), intList(1, 2, 3)}, "{ok,[1,2,3]}", "{ok,[1,2,3]}"},
+	{"empty tuple", erlgo.Tuple{}, "{}", "{}"},
+	{"nil", erlgo.Nil{}, "[]", "[]"},
+	{"improper list", erlgo.NewCons(erlgo.Int64(1), erlgo.Int64(2)), "[1|2]", "[1|2]"},
+	{"charlist", charList("hello\n"), "[104,101,108,108,111,10]", `"hello\n"`},
+	{"map", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("b"), Value: erlgo.Int64(2)}, erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)}),
+		"#{a => 1,b => 2}", "#{a => 1,b => 2}"},
+	{"empty map", erlgo.NewMap(), "#{}", "#{}"},
+	{"binary", erlgo.Binary("bin"), "<<98,105,110>>", `<<"bin">>`},
+	{"binary unprintable", erlgo.Binary{0, 255}, "<<0,255>>", "<<0,255>>"},
+	{"empty binary", erlgo.Binary{}, "<<>>", "<<>>"},
+	{"bitstring", erlgo.BitString{Bytes: []byte{1, 0xa0}, Bits: 3}, "<<1,5:3>>", "<<1,5:3>>"},
+	{"pid", erlgo.Pid{Node: "nonode@nohost", ID: 42}, "<0.42.0>", "<0.42.0>"},
+	{"port", erlgo.Port{Node: "nonode@nohost", ID: 7}, "#Port<0.7>", "#Port<0.7>"},
+	{"reference", erlgo.Reference{Node: "nonode@nohost", IDs: []uint32{1, 2, 3}}, "#Ref<0.3.2.1>", "#Ref<0.3.2.1>"},
+	{"fun", erlgo.Fun{Module: "erl_eval", OldIndex: 6, OldUniq: 99}, "#Fun<erl_eval.6.99>", "#Fun<erl_eval.6.99>"},
+	{"export", erlgo.Export{Module: "lists", Function: "map", Arity: 2}, "fun lists:map/2", "fun lists:map/2"},
+}
+
+func TestPrinting(t *testing.T) {
+	for _, test := range printTestTable {
+		if s := fmt.Sprintf("%v", test.Term); s != test.Write {
+			t.Errorf(`%v: printed as %v, expected %v.`, test.Name, s, test.Write)
+		}
+		if s := fmt.Sprint(test.Term); s != test.Write {
+			t.Errorf(`%v: String returned %v, expected %v.`, test.Name, s, test.Write)
+		}
+		if s := fmt.Sprintf("%+v", test.Term); s != test.Pretty {
+			t.Errorf(`%v: pretty printed as %v, expected %v.`, test.Name, s, test.Pretty)
+		}
+	}
+}
+
+var printDepthTestTable = []struct {
+	Name   string
+	Format string
+	Term   erlgo.Term
+	Expect string
+}{
+	{"list", "%.3v", intList(1, 2, 3, 4), "[1,2|...]"},
+	{"list at depth", "%.4v", intList(1, 2, 3), "[1,2,3]"},
+	{"tuple", "%.3v", erlgo.Tuple{erlgo.Int64(1), erlgo.Int64(2), erlgo.Int64(3), erlgo.Int64(4)}, "{1,2,...}"},
+	{"nested", "%.2v", erlgo.Tuple{erlgo.Tuple{erlgo.Int64(1)}, intList(1)}, "{{...},...}"},
+	{"nested list", "%.2v", intList(1), "[1]"},
+	{"depth one", "%.1v", intList(1), "[...]"},
+	{"binary", "%.3v", erlgo.Binary{1, 2, 3, 4}, "<<1,2,...>>"},
+	{"map", "%.2v", erlgo.NewMap(erlgo.Pair{Key: erlgo.Int64(1), Value: erlgo.Int64(1)}, erlgo.Pair{Key: erlgo.Int64(2), Value: erlgo.Int64(2)}),
+		"#{1 => 1,...}"},
+	{"string", "%+.4v", charList("abcdef"), `"abc"...`},
+	{"short string", "%+.10v", charList("abc"), `"abc"`},
+}
+
+func TestPrintingDepth(t *testing.T) {
+	for _, test := range printDepthTestTable {
+		if s := fmt.Sprintf(test.Format, test.Term); s != test.Expect {
+			t.Errorf(`%v: printed as %v, expected %v.`, test.Name, s, test.Expect)
+		}
+	}
+}
+
+func TestPrintingLineWrap(t *testing.T) {
+	term := erlgo.Tuple{erlgo.Atom("reply"), erlgo.Tuple{erlgo.Atom("ok"), intList(1, 2, 3)}, charList("a rather long string")}
+
+	expect := "{reply,{ok,[1,2,3]},\n \"a rather long string\"}"
+	if s := fmt.Sprintf("%+30v", term); s != expect {
+		t.Errorf(`printed as %v, expected %v.`, s, expect)
+	}
+
+	// elements too long for a line of their own are not filled
+	expect = "[\"a rather long string\",\n 1]"
+	if s := fmt.Sprintf("%+20v", erlgo.NewCons(charList("a rather long string"), intList(1))); s != expect {
+		t.Errorf(`printed as %v, expected %v.`, s, expect)
+	}
+
+	expect = `{reply,{ok,[1,2,3]},"a rather long string"}`
+	if s := fmt.Sprintf("%+v", term); s != expect {
+		t.Errorf(`printed as %v, expected %v.`, s, expect)
+	}
+}
+
+func TestPrintingLongList(t *testing.T) {
+	elems := make([]erlgo.Term, 40)
+	for i := range elems {
+		elems[i] = erlgo.Int64(i + 1)
+	}
+
+	expect := "[1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,\n" +
+		" 30,31,32,33,34,35,36,37,38,39,40]"
+	if s := fmt.Sprintf("%+v", erlgo.NewListFromTerms(elems)); s != expect {
+		t.Errorf(`printed as %v, expected %v.`, s, expect)
+	}
+
+	// the list does not fit on a line, so the tuple is not filled
+	expect = "{ok,\n [1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,\n  16,17,18,19,20,21,22,23,24,25,26,27,\n" +
+		"  28,29,30,31,32,33,34,35,36,37,38,39,\n  40]}"
+	if s := fmt.Sprintf("%+40v", erlgo.Tuple{erlgo.Atom("ok"), erlgo.NewListFromTerms(elems)}); s != expect {
+		t.Errorf(`printed as %v, expected %v.`, s, expect)
+	}
+}
+
+func TestPrintingOtherVerbs(t *testing.T) {
+	if s := fmt.Sprintf("%x", erlgo.Int64(255)); s != "ff" {
+		t.Errorf(`printed as %v, expected ff.`, s)
+	}
+	if s := fmt.Sprintf("%q", erlgo.Atom("ok")); s != `"ok"` {
+		t.Errorf(`printed as %v, expected "ok".`, s)
+	}
+	if s := fmt.Sprintf("%x", erlgo.Binary{1, 2}); s != "0102" {
+		t.Errorf(`printed as %v, expected 0102.`, s)
+	}
+}
@@ -3,8 +3,7 @@ package erlgo
 import (
 	"bufio"
 	"bytes"
-	"compress/zlib"
-	"fmt"
+	"io"
 )
 
 type Term interface {
@@ -17,7 +16,15 @@ type Term interface {
 }
 
 type ErlExtBinary struct {
-	bs *bufio.Reader
+	bs    *bufio.Reader
+	count *countingReader
+
+	// refs are the atoms ATOM_CACHE_REF refers to, as listed in the
+	// distribution header of the message.
+	refs []Atom
+
+	// state tracks the path and the limits of the term being decoded
+	state *decodeState
 }
 
 const (
@@ -25,6 +32,9 @@ const (
 	bitBinaryExt             = 77
 	compressed               = 80
 	atomCacheRef             = 82
+	newPidExt                = 88
+	newPortExt               = 89
+	newerReferenceExt        = 90
 	smallIntegerExt          = 97
 	integerExt               = 98
 	floatExt                 = 99
@@ -48,82 +58,148 @@ const (
 	funExt                   = 117
 	atomUtf8Ext              = 118
 	smallAtomUtf8Ext         = 119
+	v4PortExt                = 120
 )
 
-var funcMap = map[uint8]func(ErlExtBinary) (Term, error){
-	newFloatExt:        decodeNewFloat,
-	bitBinaryExt:       undefined, // TODO: as soon as there is no `undefined` left, remove that function
-	atomCacheRef:       undefined,
-	smallIntegerExt:    decodeSmallInteger,
-	integerExt:         decodeInteger,
-	floatExt:           decodeFloatExt,
-	atomExt:            undefined,
-	reference:          undefined,
-	portExt:            undefined,
-	pidExt:             undefined,
-	smallTupleExt:      undefined,
-	largeTupleExt:      undefined,
-	nilExt:             undefined,
-	stringExt:          decodeStringExt,
-	listExt:            undefined,
-	binaryExt:          undefined,
-	smallBigIntegerExt: decodeSmallBigInteger,
-	largeBigIntegerExt: decodeLargeBigInteger,
-	newFunExt:          undefined,
-	exportExt:          undefined,
-	newReferenceExt:    undefined,
-	smallAtomExt:       undefined,
-	mapExt:             undefined,
-	funExt:             undefined,
-	atomUtf8Ext:        undefined,
-	smallAtomUtf8Ext:   undefined,
-}
+// funcMap gets populated in init, since the decoders for compound terms refer
+// back to it through decodeRemaining.
+var funcMap map[uint8]func(ErlExtBinary) (Term, error)
 
-// TODO: remove this function when there is no undefined left in the map above
-func undefined(b ErlExtBinary) (Term, error) {
-	tag, _ := b.bs.ReadByte()
-	return nil, fmt.Errorf("Undefined parser for tag %v", tag)
+func init() {
+	funcMap = map[uint8]func(ErlExtBinary) (Term, error){
+		newFloatExt:        decodeNewFloat,
+		bitBinaryExt:       decodeBitBinary,
+		atomCacheRef:       decodeAtomCacheRef,
+		newPidExt:          decodePid,
+		newPortExt:         decodePort,
+		newerReferenceExt:  decodeNewReference,
+		smallIntegerExt:    decodeSmallInteger,
+		integerExt:         decodeInteger,
+		floatExt:           decodeFloatExt,
+		atomExt:            decodeAtom,
+		reference:          decodeReference,
+		portExt:            decodePort,
+		pidExt:             decodePid,
+		smallTupleExt:      decodeSmallTuple,
+		largeTupleExt:      decodeLargeTuple,
+		nilExt:             decodeNil,
+		stringExt:          decodeStringExt,
+		listExt:            decodeList,
+		binaryExt:          decodeBinary,
+		smallBigIntegerExt: decodeSmallBigInteger,
+		largeBigIntegerExt: decodeLargeBigInteger,
+		newFunExt:          decodeNewFun,
+		exportExt:          decodeExport,
+		newReferenceExt:    decodeNewReference,
+		smallAtomExt:       decodeSmallAtom,
+		mapExt:             decodeMap,
+		funExt:             decodeFun,
+		atomUtf8Ext:        decodeAtomUtf8,
+		smallAtomUtf8Ext:   decodeSmallAtomUtf8,
+		v4PortExt:          decodePort,
+	}
 }
 
 func FromBytes(data []byte) ErlExtBinary {
-	return ErlExtBinary{bufio.NewReader(bytes.NewReader(data))}
+	return newErlExtBinary(bytes.NewReader(data))
 }
 
+func newErlExtBinary(r io.Reader) ErlExtBinary {
+	count := &countingReader{r: r}
+	return ErlExtBinary{bs: bufio.NewReader(count), count: count, state: &decodeState{}}
+}
+
+// Decode decodes the next term, errors are returned as *SyntaxError.
 func (b ErlExtBinary) Decode() (Term, error) {
+	b.state.reset()
+
+	offset := b.offset()
 	if version, err := b.bs.ReadByte(); err != nil {
-		return nil, err
+		return nil, b.syntaxError(offset, 0, err)
 	} else if version != 131 {
-		return nil, fmt.Errorf("%v is an unknown version specifier", version)
+		return nil, b.syntaxError(offset, version, ErrBadVersion)
+	}
+
+	// only the outermost term may be compressed
+	offset = b.offset()
+	if tag, err := b.bs.Peek(1); err == nil && tag[0] == compressed {
+		if res, err := decodeCompressed(b); err != nil {
+			return nil, b.syntaxError(offset, compressed, err)
+		} else {
+			return res, nil
+		}
 	}
 
 	return decodeRemaining(b)
 }
 
 func decodeRemaining(b ErlExtBinary) (Term, error) {
+	offset := b.offset()
 	if tag, err := b.bs.ReadByte(); err != nil {
-		return nil, err
+		return nil, b.syntaxError(offset, 0, err)
 	} else {
-		if tag == compressed {
-			b.bs.ReadByte() // TODO: Instead of just skipping the uncompressed size, use it to verify data!
-			b.bs.ReadByte()
-			b.bs.ReadByte()
-			b.bs.ReadByte()
-			rc, err := zlib.NewReader(b.bs)
-
-			b.bs = bufio.NewReader(rc)
-			if tag, err = b.bs.ReadByte(); err != nil {
-				return nil, err
-			}
+		if err := b.state.enter(); err != nil {
+			return nil, b.syntaxError(offset, tag, err)
 		}
+		defer b.state.leave()
 
-		b.bs.UnreadByte() // TODO: as soon as undefined has been removed, we can get rid of this unreading
+		b.bs.UnreadByte() // the decoders check their tag themselves
 		if f, ok := funcMap[tag]; ok {
 			if res, err := f(b); err != nil {
-				return nil, err
+				return nil, b.syntaxError(offset, tag, err)
 			} else {
 				return res, nil
 			}
 		}
-		return nil, fmt.Errorf("%v is an unknown tag", tag)
+		return nil, b.syntaxError(offset, tag, ErrUnsupportedTag)
+	}
+}
+
+func readUint16(b ErlExtBinary) (uint16, error) {
+	if b1, err := b.bs.ReadByte(); err != nil {
+		return 0, err
+	} else if b2, err := b.bs.ReadByte(); err != nil {
+		return 0, err
+	} else {
+		return uint16(b1)<<8 | uint16(b2), nil
+	}
+}
+
+// maxPrealloc caps the capacity reserved for the elements a header announces,
+// as the count alone does not prove that the elements follow.
+const maxPrealloc = 1024
+
+const readChunkSize = 64 * 1024
+
+func initialCapacity(count uint32) int {
+	if count > maxPrealloc {
+		return maxPrealloc
+	}
+	return int(count)
+}
+
+// readBytes reads n bytes in chunks, so that a length announced by truncated
+// input fails before the memory for all of it is allocated.
+func readBytes(b ErlExtBinary, n int) ([]byte, error) {
+	return readFull(b.bs, n)
+}
+
+// readFull reads exactly n bytes like io.ReadFull, but allocates larger
+// amounts chunk by chunk as they arrive.
+func readFull(r io.Reader, n int) ([]byte, error) {
+	if n <= readChunkSize {
+		result := make([]byte, n)
+		if _, err := io.ReadFull(r, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(n)); err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
 }
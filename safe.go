@@ -0,0 +1,58 @@
+package erlgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AtomRegistry tells which atoms may be decoded in safe mode.
+type AtomRegistry interface {
+	Known(Atom) bool
+}
+
+// AtomSet is an allow-list of atoms.
+type AtomSet map[Atom]bool
+
+func NewAtomSet(atoms ...Atom) AtomSet {
+	set := make(AtomSet, len(atoms))
+	for _, atom := range atoms {
+		set[atom] = true
+	}
+	return set
+}
+
+func (s AtomSet) Known(a Atom) bool {
+	return s[a]
+}
+
+// UnknownAtomError reports an atom rejected in safe mode.
+type UnknownAtomError struct {
+	Atom Atom
+}
+
+func (e *UnknownAtomError) Error() string {
+	return fmt.Sprintf("atom %q is not known in safe mode", string(e.Atom))
+}
+
+// ErrUnsafeFun is returned for funs and exports in safe mode.
+var ErrUnsafeFun = errors.New("funs and exports are rejected in safe mode")
+
+func (s *decodeState) checkAtom(a Atom) error {
+	if s == nil || !s.opts.Safe {
+		return nil
+	}
+
+	if s.opts.KnownAtoms == nil || !s.opts.KnownAtoms.Known(a) {
+		return &UnknownAtomError{Atom: a}
+	}
+
+	return nil
+}
+
+func (s *decodeState) checkFun() error {
+	if s != nil && s.opts.Safe {
+		return ErrUnsafeFun
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var safeOptions = erlgo.DecoderOptions{
+	Safe:       true,
+	KnownAtoms: erlgo.NewAtomSet("ok", "error", "nonode@nohost"),
+}
+
+var safeTestTable = []struct {
+	Name   string
+	Data   []byte
+	Expect erlgo.Term
+}{
+	{"known atom", []byte{131, 119, 2, 'o', 'k'}, erlgo.Atom("ok")},
+	{"known latin1 atom", []byte{131, 100, 0, 5, 'e', 'r', 'r', 'o', 'r'}, erlgo.Atom("error")},
+	{"tuple of known atoms", []byte{131, 104, 2, 119, 2, 'o', 'k', 97, 1}, erlgo.Tuple{erlgo.Atom("ok"), erlgo.Int64(1)}},
+	{"pid on a known node", append(append([]byte{131, 88}, nodeSmallAtom...), 0, 0, 0, 42, 0, 0, 0, 1, 0, 0, 0, 3),
+		erlgo.Pid{Node: "nonode@nohost", ID: 42, Serial: 1, Creation: 3}},
+}
+
+func TestSafeDecoding(t *testing.T) {
+	for _, test := range safeTestTable {
+		if val, err := erlgo.FromBytes(test.Data).WithOptions(safeOptions).Decode(); err != nil {
+			t.Errorf(`%v encountered error "%v", expected %#v.`, test.Name, err, test.Expect)
+		} else if !val.Matches(test.Expect) {
+			t.Errorf(`%v parsed into %#v, expected %#v.`, test.Name, val, test.Expect)
+		}
+	}
+}
+
+func TestSafeDecodingRejectsUnknownAtoms(t *testing.T) {
+	for _, test := range []struct {
+		Name   string
+		Data   []byte
+		Expect erlgo.Atom
+	}{
+		{"unknown atom", []byte{131, 119, 3, 'f', 'o', 'o'}, "foo"},
+		{"nested unknown atom", []byte{131, 104, 2, 119, 2, 'o', 'k', 115, 3, 'b', 'a', 'r'}, "bar"},
+		{"map key", []byte{131, 116, 0, 0, 0, 1, 119, 3, 'k', 'e', 'y', 97, 1}, "key"},
+		{"pid on an unknown node", append(append([]byte{131, 88}, 119, 3, 'a', '@', 'b'), 0, 0, 0, 42, 0, 0, 0, 1, 0, 0, 0, 3), "a@b"},
+	} {
+		var atomErr *erlgo.UnknownAtomError
+		if val, err := erlgo.FromBytes(test.Data).WithOptions(safeOptions).Decode(); !errors.As(err, &atomErr) {
+			t.Errorf(`%v parsed into (%#v, %v), expected an *UnknownAtomError.`, test.Name, val, err)
+		} else if atomErr.Atom != test.Expect {
+			t.Errorf(`%v rejected %q, expected %q.`, test.Name, atomErr.Atom, test.Expect)
+		}
+	}
+
+	// without an allow-list no atom is known
+	if val, err := erlgo.FromBytes([]byte{131, 119, 2, 'o', 'k'}).WithOptions(erlgo.DecoderOptions{Safe: true}).Decode(); err == nil {
+		t.Errorf(`decoding without known atoms returned %#v, expected an error.`, val)
+	}
+}
+
+func TestSafeDecodingRejectsFuns(t *testing.T) {
+	opts := safeOptions
+	opts.KnownAtoms = erlgo.NewAtomSet("lists", "map", "erl_eval", "nonode@nohost")
+
+	for _, test := range []struct {
+		Name string
+		Data []byte
+	}{
+		{"new fun", newFunData()},
+		{"legacy fun", legacyFunData()},
+		{"export", []byte{131, 113, 119, 5, 108, 105, 115, 116, 115, 119, 3, 109, 97, 112, 97, 2}},
+		{"fun in a list", append(append([]byte{131, 108, 0, 0, 0, 1}, newFunData()[1:]...), 106)},
+	} {
+		if val, err := erlgo.FromBytes(test.Data).WithOptions(opts).Decode(); !errors.Is(err, erlgo.ErrUnsafeFun) {
+			t.Errorf(`%v parsed into (%#v, %v), expected ErrUnsafeFun.`, test.Name, val, err)
+		}
+		if _, err := erlgo.FromBytes(test.Data).Decode(); err != nil {
+			t.Errorf(`%v encountered error "%v" outside of safe mode.`, test.Name, err)
+		}
+	}
+}
+
+func TestSafeDecoder(t *testing.T) {
+	d := erlgo.NewDecoder(bytes.NewReader([]byte{131, 119, 2, 'o', 'k', 131, 119, 3, 'f', 'o', 'o'}))
+	d.SetOptions(safeOptions)
+
+	if val, err := d.Decode(); err != nil || !val.Matches(erlgo.Atom("ok")) {
+		t.Errorf(`decoded (%#v, %v), expected ok.`, val, err)
+	}
+	if val, err := d.Decode(); err == nil {
+		t.Errorf(`decoded %#v, expected foo to be rejected.`, val)
+	}
+}
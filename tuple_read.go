@@ -0,0 +1,98 @@
+package erlgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+type Tuple []Term
+
+func (t Tuple) IsInteger() bool {
+	return false
+}
+
+func (t Tuple) IsList() bool { return false }
+
+func (t Tuple) ToInteger() (Int, error) {
+	return nil, errors.New("Not an Integer")
+}
+
+func (t Tuple) Arity() int {
+	return len(t)
+}
+
+// Element returns the n-th element of the tuple. Just as erlang:element/2 it
+// is 1-based.
+func (t Tuple) Element(n int) (Term, error) {
+	if n < 1 || n > len(t) {
+		return nil, fmt.Errorf("%v is out of range for a tuple of arity %v", n, len(t))
+	}
+	return t[n-1], nil
+}
+
+func (t Tuple) Matches(other Term) bool {
+	switch o := other.(type) {
+	case Tuple:
+		if len(t) != len(o) {
+			return false
+		}
+		for i := range t {
+			if !t[i].Matches(o[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func decodeSmallTuple(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != smallTupleExt {
+		return nil, fmt.Errorf("%v is not tagging a small tuple", tag)
+	}
+
+	arity, err := b.bs.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	return readTupleElements(b, uint32(arity))
+}
+
+func decodeLargeTuple(b ErlExtBinary) (Term, error) {
+	if tag, err := b.bs.ReadByte(); err != nil {
+		return nil, err
+	} else if tag != largeTupleExt {
+		return nil, fmt.Errorf("%v is not tagging a large tuple", tag)
+	}
+
+	arity, err := readInt32(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return readTupleElements(b, uint32(arity))
+}
+
+func readTupleElements(b ErlExtBinary, arity uint32) (Term, error) {
+	if err := b.state.checkElements(uint64(arity)); err != nil {
+		return nil, err
+	}
+
+	result := make(Tuple, 0, initialCapacity(arity))
+
+	for i := uint32(0); i < arity; i++ {
+		b.state.push("tuple", int(i), nil)
+		if elem, err := decodeRemaining(b); err != nil {
+			return nil, err
+		} else {
+			result = append(result, elem)
+		}
+		b.state.pop()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+func largeTupleData(arity int) ([]byte, erlgo.Tuple) {
+	data := []byte{131, 105, byte(arity >> 24), byte(arity >> 16), byte(arity >> 8), byte(arity)}
+	expect := make(erlgo.Tuple, arity)
+	for i := 0; i < arity; i++ {
+		data = append(data, 97, byte(i))
+		expect[i] = erlgo.Int64(byte(i))
+	}
+	return data, expect
+}
+
+var largeTuple, largeTupleExpect = largeTupleData(300)
+
+var tupleTestTable = []struct {
+	Name   string
+	Data   erlgo.ErlExtBinary
+	Expect erlgo.Term
+}{
+	{"{}", erlgo.FromBytes([]byte{131, 104, 0}), erlgo.Tuple{}},
+	{"{ok, 1}", erlgo.FromBytes([]byte{131, 104, 2, 119, 2, 111, 107, 97, 1}), erlgo.Tuple{erlgo.Atom("ok"), erlgo.Int64(1)}},
+	{"{error, {not_found, 1.0}}", erlgo.FromBytes([]byte{131, 104, 2, 119, 5, 101, 114, 114, 111, 114, 104, 2, 119, 9, 110, 111, 116, 95, 102, 111, 117, 110, 100, 70, 63, 240, 0, 0, 0, 0, 0, 0}), erlgo.Tuple{erlgo.Atom("error"), erlgo.Tuple{erlgo.Atom("not_found"), erlgo.Float(1.0)}}},
+	{"{} (large)", erlgo.FromBytes([]byte{131, 105, 0, 0, 0, 0}), erlgo.Tuple{}},
+	{"300-tuple (large)", erlgo.FromBytes(largeTuple), largeTupleExpect},
+}
+
+func TestReadingTuples(t *testing.T) {
+	for _, test := range tupleTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if val, err := test.Data.Decode(); err == nil && !val.Matches(test.Expect) {
+				t.Errorf(`%#v parsed into %#v, expected %#v."`, test.Data, val, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected value %#v.`, test.Data, err, test.Expect)
+			}
+		})
+	}
+}
+
+func TestTupleMatches(t *testing.T) {
+	okOne := erlgo.Tuple{erlgo.Atom("ok"), erlgo.Int64(1)}
+	for _, test := range []struct {
+		Name   string
+		Other  erlgo.Term
+		Expect bool
+	}{
+		{"same", erlgo.Tuple{erlgo.Atom("ok"), erlgo.Int64(1)}, true},
+		{"different element", erlgo.Tuple{erlgo.Atom("ok"), erlgo.Int64(2)}, false},
+		{"different arity", erlgo.Tuple{erlgo.Atom("ok")}, false},
+		{"not a tuple", erlgo.Atom("ok"), false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if okOne.Matches(test.Other) != test.Expect {
+				t.Errorf(`%#v.Matches(%#v) returned %v, expected %v.`, okOne, test.Other, !test.Expect, test.Expect)
+			}
+		})
+	}
+}
+
+func TestTupleElement(t *testing.T) {
+	tuple := erlgo.Tuple{erlgo.Atom("ok"), erlgo.Int64(1)}
+
+	if tuple.Arity() != 2 {
+		t.Errorf(`%#v.Arity() returned %v, expected 2.`, tuple, tuple.Arity())
+	}
+	if elem, err := tuple.Element(1); err != nil || !elem.Matches(erlgo.Atom("ok")) {
+		t.Errorf(`%#v.Element(1) returned (%#v, %v), expected ok.`, tuple, elem, err)
+	}
+	if elem, err := tuple.Element(2); err != nil || !elem.Matches(erlgo.Int64(1)) {
+		t.Errorf(`%#v.Element(2) returned (%#v, %v), expected 1.`, tuple, elem, err)
+	}
+	for _, n := range []int{0, 3} {
+		if elem, err := tuple.Element(n); err == nil {
+			t.Errorf(`%#v.Element(%v) returned %#v, expected an error.`, tuple, n, elem)
+		}
+	}
+}
+
+func BenchmarkReadingTuples(b *testing.B) {
+	for _, data := range tupleTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				data.Data.Decode()
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package erlgo
+
+import (
+	"bytes"
+	"math"
+)
+
+func encodeTuple(buf *bytes.Buffer, t Tuple) error {
+	if len(t) <= math.MaxUint8 {
+		buf.WriteByte(smallTupleExt)
+		buf.WriteByte(byte(len(t)))
+	} else {
+		buf.WriteByte(largeTupleExt)
+		writeUint32(buf, uint32(len(t)))
+	}
+
+	for _, elem := range t {
+		if err := encodeTerm(buf, elem); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"testing"
+)
+
+var tupleWriteTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Expect []byte
+}{
+	{"{}", erlgo.Tuple{}, []byte{131, 104, 0}},
+	{"{ok, 1}", erlgo.Tuple{erlgo.Atom("ok"), erlgo.Int64(1)}, []byte{131, 104, 2, 119, 2, 111, 107, 97, 1}},
+	{"300-tuple", largeTupleExpect, largeTuple},
+}
+
+func TestWritingTuples(t *testing.T) {
+	for _, test := range tupleWriteTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil && !bytes.Equal(data, test.Expect) {
+				t.Errorf(`%#v encoded into %v, expected %v.`, test.Term, data, test.Expect)
+			} else if err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %v.`, test.Term, err, test.Expect)
+			}
+		})
+	}
+}
+
+func BenchmarkWritingTuples(b *testing.B) {
+	for _, data := range tupleWriteTestTable {
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Encode(data.Term)
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package erlgo
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// An UnmarshalTypeError describes a term that can not be stored in a Go value
+// of a specific type.
+type UnmarshalTypeError struct {
+	Term Term
+	Type reflect.Type
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	return fmt.Sprintf("erlgo: cannot unmarshal %#v into Go value of type %v", e.Term, e.Type)
+}
+
+// An OverflowError describes an integer or float that does not fit into the
+// Go value it is unmarshalled into.
+type OverflowError struct {
+	Term Term
+	Type reflect.Type
+}
+
+func (e *OverflowError) Error() string {
+	return fmt.Sprintf("erlgo: %#v overflows Go value of type %v", e.Term, e.Type)
+}
+
+// An InvalidUnmarshalError describes an invalid argument passed to Unmarshal.
+type InvalidUnmarshalError struct {
+	Type reflect.Type
+}
+
+func (e *InvalidUnmarshalError) Error() string {
+	if e.Type == nil {
+		return "erlgo: Unmarshal(nil)"
+	}
+	return fmt.Sprintf("erlgo: Unmarshal(non-pointer %v)", e.Type)
+}
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// Unmarshal decodes data in the external term format and stores the result in
+// the value pointed to by v.
+//
+// Atoms are stored in strings and bools, integers in any sized integer or in a
+// big.Int, floats in floats, binaries in byte slices and strings, lists and
+// tuples in slices and arrays, maps in Go maps and structs. A tuple is stored
+// in a struct field by field, with a leading atom tag being skipped, when
+// the tuple has one more element than the struct has fields. Structs
+// embedding Record only accept tuples tagged with their record name. The
+// atoms `nil` and `undefined` set pointers to nil. Values of a type the term
+// is assignable to, like Term or interface{}, receive the term as is.
+//
+// Struct fields are matched to map keys by the name given in an `erl` struct
+// tag, or by their name otherwise. Fields tagged with `erl:"-"` are ignored.
+func Unmarshal(data []byte, v interface{}) error {
+	term, err := FromBytes(data).Decode()
+	if err != nil {
+		return err
+	}
+
+	return UnmarshalTerm(term, v)
+}
+
+// UnmarshalTerm stores an already decoded term in the value pointed to by v,
+// following the same rules as Unmarshal.
+func UnmarshalTerm(t Term, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
+	} else if t == nil {
+		return &UnmarshalTypeError{t, rv.Type().Elem()}
+	}
+
+	return unmarshalValue(t, rv.Elem())
+}
+
+func unmarshalValue(t Term, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if isNilAtom(t) {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return unmarshalValue(t, v.Elem())
+	}
+
+	if tv := reflect.ValueOf(t); tv.Type().AssignableTo(v.Type()) {
+		v.Set(tv)
+		return nil
+	}
+
+	if v.Type() == bigIntType {
+		i, err := t.ToInteger()
+		if err != nil {
+			return &UnmarshalTypeError{t, v.Type()}
+		}
+		v.Set(reflect.ValueOf(*new(big.Int).Set(i.BigInt())))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if a, ok := t.(Atom); ok && a.IsBool() {
+			b, _ := a.ToBool()
+			v.SetBool(b)
+			return nil
+		}
+	case reflect.String:
+		if s, ok := termToString(t); ok {
+			v.SetString(s)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return unmarshalInt(t, v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return unmarshalUint(t, v)
+	case reflect.Float32, reflect.Float64:
+		return unmarshalFloat(t, v)
+	case reflect.Slice:
+		return unmarshalSlice(t, v)
+	case reflect.Array:
+		return unmarshalArray(t, v)
+	case reflect.Map:
+		return unmarshalMap(t, v)
+	case reflect.Struct:
+		return unmarshalStruct(t, v)
+	}
+
+	return &UnmarshalTypeError{t, v.Type()}
+}
+
+func isNilAtom(t Term) bool {
+	a, ok := t.(Atom)
+	return ok && (a == "nil" || a == "undefined")
+}
+
+// termToString converts atoms, binaries and lists of unicode code points to a
+// string.
+func termToString(t Term) (string, bool) {
+	switch v := t.(type) {
+	case Atom:
+		return string(v), true
+	case Binary:
+		return string(v), true
+	case List:
+		elements, err := v.ToSlice()
+		if err != nil {
+			return "", false
+		}
+
+		runes := make([]rune, len(elements))
+		for i, elem := range elements {
+			c, ok := elem.(Int64)
+			if !ok || c < 0 || c > utf8.MaxRune {
+				return "", false
+			}
+			runes[i] = rune(c)
+		}
+		return string(runes), true
+	default:
+		return "", false
+	}
+}
+
+func unmarshalInt(t Term, v reflect.Value) error {
+	i, err := t.ToInteger()
+	if err != nil {
+		return &UnmarshalTypeError{t, v.Type()}
+	}
+
+	n, ok := i.Int64()
+	if !ok || v.OverflowInt(n) {
+		return &OverflowError{t, v.Type()}
+	}
+
+	v.SetInt(n)
+	return nil
+}
+
+func unmarshalUint(t Term, v reflect.Value) error {
+	i, err := t.ToInteger()
+	if err != nil {
+		return &UnmarshalTypeError{t, v.Type()}
+	}
+
+	n := i.BigInt()
+	if n.Sign() < 0 || n.BitLen() > 64 || v.OverflowUint(n.Uint64()) {
+		return &OverflowError{t, v.Type()}
+	}
+
+	v.SetUint(n.Uint64())
+	return nil
+}
+
+func unmarshalFloat(t Term, v reflect.Value) error {
+	var f float64
+
+	switch n := t.(type) {
+	case Float:
+		f = float64(n)
+	case Int64:
+		f = float64(n)
+	case IntBig:
+		f, _ = new(big.Float).SetInt(n.Int).Float64()
+		if math.IsInf(f, 0) {
+			return &OverflowError{t, v.Type()}
+		}
+	default:
+		return &UnmarshalTypeError{t, v.Type()}
+	}
+
+	if v.OverflowFloat(f) {
+		return &OverflowError{t, v.Type()}
+	}
+
+	v.SetFloat(f)
+	return nil
+}
+
+// termElements returns the elements of proper lists and tuples.
+func termElements(t Term) ([]Term, bool) {
+	switch v := t.(type) {
+	case Tuple:
+		return v, true
+	case List:
+		elements, err := v.ToSlice()
+		return elements, err == nil
+	default:
+		return nil, false
+	}
+}
+
+func unmarshalSlice(t Term, v reflect.Value) error {
+	if v.Type().Elem().Kind() == reflect.Uint8 {
+		if bin, ok := t.(Binary); ok {
+			v.SetBytes(append([]byte{}, bin...))
+			return nil
+		}
+	}
+
+	elements, ok := termElements(t)
+	if !ok {
+		return &UnmarshalTypeError{t, v.Type()}
+	}
+
+	result := reflect.MakeSlice(v.Type(), len(elements), len(elements))
+	for i, elem := range elements {
+		if err := unmarshalValue(elem, result.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	v.Set(result)
+	return nil
+}
+
+func unmarshalArray(t Term, v reflect.Value) error {
+	elements, ok := termElements(t)
+	if !ok || len(elements) != v.Len() {
+		return &UnmarshalTypeError{t, v.Type()}
+	}
+
+	for i, elem := range elements {
+		if err := unmarshalValue(elem, v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func unmarshalMap(t Term, v reflect.Value) error {
+	m, ok := t.(Map)
+	if !ok {
+		return &UnmarshalTypeError{t, v.Type()}
+	}
+
+	result := reflect.MakeMap(v.Type())
+	for _, p := range m.pairs {
+		key := reflect.New(v.Type().Key()).Elem()
+		if err := unmarshalValue(p.Key, key); err != nil {
+			return err
+		}
+
+		value := reflect.New(v.Type().Elem()).Elem()
+		if err := unmarshalValue(p.Value, value); err != nil {
+			return err
+		}
+
+		result.SetMapIndex(key, value)
+	}
+
+	v.Set(result)
+	return nil
+}
+
+func unmarshalStruct(t Term, v reflect.Value) error {
+	fields := structFields(v.Type())
+
+	switch s := t.(type) {
+	case Map:
+		for _, p := range s.pairs {
+			name, ok := keyName(p.Key)
+			if !ok {
+				continue
+			}
+			if f, ok := fieldByName(fields, name); ok {
+				if err := unmarshalValue(p.Value, v.FieldByIndex(f.index)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case Tuple:
+		elements := []Term(s)
+		if name, ok := recordName(v.Type()); ok {
+			if tag, ok := recordTag(s); !ok || tag != name || len(elements) != len(fields)+1 {
+				return &UnmarshalTypeError{t, v.Type()}
+			}
+			elements = elements[1:]
+		} else if _, ok := recordTag(s); ok && len(elements) == len(fields)+1 {
+			elements = elements[1:]
+		}
+		if len(elements) != len(fields) {
+			return &UnmarshalTypeError{t, v.Type()}
+		}
+		for i, f := range fields {
+			if err := unmarshalValue(elements[i], v.FieldByIndex(f.index)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return &UnmarshalTypeError{t, v.Type()}
+	}
+}
+
+// keyName returns the text of map keys which can name a struct field.
+func keyName(t Term) (string, bool) {
+	switch k := t.(type) {
+	case Atom:
+		return string(k), true
+	case Binary:
+		return string(k), true
+	default:
+		return "", false
+	}
+}
+
+// recordTag returns the leading atom of a tagged tuple.
+func recordTag(t Tuple) (Atom, bool) {
+	if len(t) == 0 {
+		return "", false
+	}
+	a, ok := t[0].(Atom)
+	return a, ok
+}
+
+type field struct {
+	name    string
+	index   []int
+	options []string
+}
+
+func (f field) hasOption(option string) bool {
+	for _, o := range f.options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// structFields lists the exported fields of a struct in declaration order,
+// together with the name and options from their `erl` tag.
+func structFields(t reflect.Type) []field {
+	result := []field{}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" || sf.Anonymous && sf.Type == recordType {
+			continue
+		}
+
+		tag := sf.Tag.Get("erl")
+		if tag == "-" {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		f := field{name: parts[0], index: sf.Index, options: parts[1:]}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+
+		result = append(result, f)
+	}
+
+	return result
+}
+
+// fieldByName prefers an exact match over a case insensitive one.
+func fieldByName(fields []field, name string) (field, bool) {
+	for _, f := range fields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.name, name) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
@@ -0,0 +1,136 @@
+package erlgo_test
+
+import (
+	"github.com/NobbZ/erlgo"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type person struct {
+	Name    string `erl:"name"`
+	Age     uint8  `erl:"age"`
+	Email   *string
+	Ignored int `erl:"-"`
+}
+
+type point struct {
+	X, Y int
+}
+
+func newString(s string) *string {
+	return &s
+}
+
+var unmarshalTestTable = []struct {
+	Name   string
+	Term   erlgo.Term
+	Target interface{}
+	Expect interface{}
+}{
+	{"atom into string", erlgo.Atom("ok"), new(string), "ok"},
+	{"binary into string", erlgo.Binary("héllo"), new(string), "héllo"},
+	{"charlist into string", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(104), erlgo.Int64(233)}), new(string), "hé"},
+	{"true into bool", erlgo.True, new(bool), true},
+	{"false into bool", erlgo.False, new(bool), false},
+	{"integer into int8", erlgo.Int64(-128), new(int8), int8(-128)},
+	{"integer into uint64", erlgo.IntBig{new(big.Int).SetUint64(1<<64 - 1)}, new(uint64), uint64(1<<64 - 1)},
+	{"big integer into big.Int", erlgo.IntBig{plusNine}, new(*big.Int), plusNine},
+	{"small integer into big.Int", erlgo.Int64(5), new(big.Int), *big.NewInt(5)},
+	{"float into float32", erlgo.Float(1.5), new(float32), float32(1.5)},
+	{"integer into float64", erlgo.Int64(2), new(float64), float64(2)},
+	{"binary into []byte", erlgo.Binary{1, 2}, new([]byte), []byte{1, 2}},
+	{"list into []int", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Int64(1), erlgo.Int64(1000)}), new([]int), []int{1, 1000}},
+	{"nil into []int", erlgo.Nil{}, new([]int), []int{}},
+	{"tuple into [2]string", erlgo.Tuple{erlgo.Atom("a"), erlgo.Binary("b")}, new([2]string), [2]string{"a", "b"}},
+	{"map into map[string]int", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("a"), Value: erlgo.Int64(1)}), new(map[string]int), map[string]int{"a": 1}},
+	{"map into struct", erlgo.NewMap(
+		erlgo.Pair{Key: erlgo.Atom("name"), Value: erlgo.Binary("Joe")},
+		erlgo.Pair{Key: erlgo.Binary("age"), Value: erlgo.Int64(42)},
+		erlgo.Pair{Key: erlgo.Atom("email"), Value: erlgo.Binary("joe@example.com")},
+		erlgo.Pair{Key: erlgo.Atom("Ignored"), Value: erlgo.Int64(1)},
+		erlgo.Pair{Key: erlgo.Atom("unknown"), Value: erlgo.Int64(1)},
+	), new(person), person{Name: "Joe", Age: 42, Email: newString("joe@example.com")}},
+	{"undefined into pointer", erlgo.NewMap(erlgo.Pair{Key: erlgo.Atom("email"), Value: erlgo.Atom("undefined")}), &person{Email: newString("x")}, person{}},
+	{"tagged tuple into struct", erlgo.Tuple{erlgo.Atom("point"), erlgo.Int64(1), erlgo.Int64(2)}, new(point), point{1, 2}},
+	{"tuple into struct", erlgo.Tuple{erlgo.Int64(1), erlgo.Int64(2)}, new(point), point{1, 2}},
+	{"term into interface{}", erlgo.Tuple{erlgo.Atom("ok")}, new(interface{}), erlgo.Tuple{erlgo.Atom("ok")}},
+	{"term into Term", erlgo.Atom("ok"), new(erlgo.Term), erlgo.Atom("ok")},
+	{"tuple into []Term", erlgo.Tuple{erlgo.Atom("ok")}, new([]erlgo.Term), []erlgo.Term{erlgo.Atom("ok")}},
+}
+
+func TestUnmarshal(t *testing.T) {
+	for _, test := range unmarshalTestTable {
+		t.Run(test.Name, func(t *testing.T) {
+			data, err := erlgo.Encode(test.Term)
+			if err != nil {
+				t.Fatalf(`%#v encountered error "%v" while encoding.`, test.Term, err)
+			}
+
+			if err := erlgo.Unmarshal(data, test.Target); err != nil {
+				t.Errorf(`%#v encountered error "%v", expected %#v.`, test.Term, err, test.Expect)
+			} else if val := reflect.ValueOf(test.Target).Elem().Interface(); !reflect.DeepEqual(val, test.Expect) {
+				t.Errorf(`%#v unmarshalled into %#v, expected %#v.`, test.Term, val, test.Expect)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	for _, test := range []struct {
+		Name     string
+		Term     erlgo.Term
+		Target   interface{}
+		Overflow bool
+	}{
+		{"integer into string", erlgo.Int64(1), new(string), false},
+		{"ok into bool", erlgo.Atom("ok"), new(bool), false},
+		{"256 into uint8", erlgo.Int64(256), new(uint8), true},
+		{"-1 into uint", erlgo.Int64(-1), new(uint), true},
+		{"big integer into int64", erlgo.IntBig{plusNine}, new(int64), true},
+		{"huge float into float32", erlgo.Float(1e300), new(float32), true},
+		{"improper list into slice", erlgo.NewCons(erlgo.Int64(1), erlgo.Int64(2)), new([]int), false},
+		{"tuple of wrong size into struct", erlgo.Tuple{erlgo.Int64(1)}, new(point), false},
+		{"tuple of wrong size into array", erlgo.Tuple{erlgo.Int64(1)}, new([2]int), false},
+		{"nested error", erlgo.NewListFromTerms([]erlgo.Term{erlgo.Atom("a")}), new([]int), false},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			data, err := erlgo.Encode(test.Term)
+			if err != nil {
+				t.Fatalf(`%#v encountered error "%v" while encoding.`, test.Term, err)
+			}
+
+			switch err := erlgo.Unmarshal(data, test.Target).(type) {
+			case *erlgo.OverflowError:
+				if !test.Overflow {
+					t.Errorf(`%#v returned overflow error "%v", expected a type error.`, test.Term, err)
+				}
+			case *erlgo.UnmarshalTypeError:
+				if test.Overflow {
+					t.Errorf(`%#v returned type error "%v", expected an overflow error.`, test.Term, err)
+				}
+			default:
+				t.Errorf(`%#v returned %#v, expected an error.`, test.Term, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalInvalidTarget(t *testing.T) {
+	for _, target := range []interface{}{nil, 1, (*int)(nil)} {
+		if err, ok := erlgo.Unmarshal([]byte{131, 97, 1}, target).(*erlgo.InvalidUnmarshalError); !ok {
+			t.Errorf(`unmarshalling into %#v returned %#v, expected an InvalidUnmarshalError.`, target, err)
+		}
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	for _, data := range unmarshalTestTable {
+		encoded, _ := erlgo.Encode(data.Term)
+		b.Run(data.Name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				erlgo.Unmarshal(encoded, reflect.New(reflect.TypeOf(data.Target).Elem()).Interface())
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package erlgo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+type Encoder struct {
+	w     io.Writer
+	level int
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the version byte followed by the term in the external term
+// format, using the smallest tag available for each value.
+func (e *Encoder) Encode(t Term) error {
+	buf := bytes.NewBuffer([]byte{131})
+
+	if err := encodeTerm(buf, t); err != nil {
+		return err
+	}
+
+	if e.level > 0 {
+		var err error
+		if buf, err = compressTerm(buf, e.level); err != nil {
+			return err
+		}
+	}
+
+	_, err := buf.WriteTo(e.w)
+	return err
+}
+
+func Encode(t Term) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := NewEncoder(&buf).Encode(t); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func encodeTerm(buf *bytes.Buffer, t Term) error {
+	switch v := t.(type) {
+	case Int64:
+		return encodeInt64(buf, v)
+	case IntBig:
+		return encodeIntBig(buf, v)
+	case Float:
+		return encodeFloat(buf, v)
+	case Atom:
+		return encodeAtom(buf, v)
+	case Nil:
+		return buf.WriteByte(nilExt)
+	case Cons:
+		return encodeList(buf, v)
+	case Tuple:
+		return encodeTuple(buf, v)
+	case Binary:
+		return encodeBinary(buf, v)
+	case BitString:
+		return encodeBitString(buf, v)
+	case Map:
+		return encodeMap(buf, v)
+	case Pid:
+		return encodePid(buf, v)
+	case Port:
+		return encodePort(buf, v)
+	case Reference:
+		return encodeReference(buf, v)
+	case Fun:
+		return encodeFun(buf, v)
+	case Export:
+		return encodeExport(buf, v)
+	default:
+		return fmt.Errorf("%#v can not be encoded", t)
+	}
+}
+
+func writeUint16(buf *bytes.Buffer, v uint16) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], v)
+	buf.Write(b[:])
+}
+
+func writeUint32(buf *bytes.Buffer, v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	buf.Write(b[:])
+}
+
+func writeUint64(buf *bytes.Buffer, v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	buf.Write(b[:])
+}
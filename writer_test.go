@@ -0,0 +1,76 @@
+package erlgo_test
+
+import (
+	"bytes"
+	"github.com/NobbZ/erlgo"
+	"strings"
+	"testing"
+)
+
+var roundTripTables = map[string][]struct {
+	Name   string
+	Data   erlgo.ErlExtBinary
+	Expect erlgo.Term
+}{
+	"atoms":       atomTestTable,
+	"binaries":    binaryTestTable,
+	"floats":      floatTestTable,
+	"funs":        funTestTable,
+	"identifiers": identifierTestTable,
+	"integers":    integerTestTable,
+	"lists":       listTestTable,
+	"maps":        mapTestTable,
+	"tuples":      tupleTestTable,
+}
+
+func TestRoundTrip(t *testing.T) {
+	for kind, table := range roundTripTables {
+		for _, test := range table {
+			t.Run(kind+"/"+test.Name, func(t *testing.T) {
+				if data, err := erlgo.Encode(test.Expect); err != nil {
+					t.Errorf(`%#v encountered error "%v" while encoding.`, test.Expect, err)
+				} else if val, err := erlgo.FromBytes(data).Decode(); err != nil {
+					t.Errorf(`%v encountered error "%v", expected value %#v.`, data, err, test.Expect)
+				} else if !val.Matches(test.Expect) {
+					t.Errorf(`%#v was encoded into %v and parsed into %#v.`, test.Expect, data, val)
+				}
+			})
+		}
+	}
+}
+
+func TestEncoderWritesSuccessiveTerms(t *testing.T) {
+	var buf bytes.Buffer
+	enc := erlgo.NewEncoder(&buf)
+
+	for _, term := range []erlgo.Term{erlgo.Int64(1), erlgo.Atom("ok")} {
+		if err := enc.Encode(term); err != nil {
+			t.Fatalf(`%#v encountered error "%v".`, term, err)
+		}
+	}
+
+	if expect := []byte{131, 97, 1, 131, 119, 2, 111, 107}; !bytes.Equal(buf.Bytes(), expect) {
+		t.Errorf(`encoder wrote %v, expected %v.`, buf.Bytes(), expect)
+	}
+}
+
+type unknownTerm struct{ erlgo.Atom }
+
+func TestWritingInvalidTerms(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Term erlgo.Term
+	}{
+		{"unknown term", unknownTerm{"ok"}},
+		{"atom too long", erlgo.Atom(strings.Repeat("a", 256))},
+		{"invalid utf8 atom", erlgo.Atom([]byte{246})},
+		{"invalid bitstring", erlgo.BitString{Bytes: []byte{1}, Bits: 9}},
+		{"nested unknown term", erlgo.Tuple{erlgo.Int64(1), unknownTerm{"ok"}}},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			if data, err := erlgo.Encode(test.Term); err == nil {
+				t.Errorf(`%#v encoded into %v, expected an error.`, test.Term, data)
+			}
+		})
+	}
+}